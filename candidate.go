@@ -0,0 +1,102 @@
+package ptime
+
+import "sort"
+
+const (
+	ruleAlternate  = "alternate"
+	ruleLocale     = "locale"
+	ruleMonthName  = "month-name"
+	ruleOrdinalDay = "ordinal-day"
+	ruleOrdinal    = "ordinal-suffix"
+	ruleSeparator  = "separator"
+)
+
+// Candidate is one possible interpretation of the text given to ParseAll.
+// Order is the order of the date fields that was used (e.g.
+// "month-day-year") and Rule is the reason that order was chosen:
+//
+//	locale          the order preferred by the locale
+//	month-name      the position of a month name
+//	ordinal-day     a three digit day after the year
+//	ordinal-suffix  a day with an ordinal suffix, such as "1st", before
+//	                the month
+//	separator       a "-" separator which implies year-month-day
+//	alternate       an order that was not chosen but is also valid
+//
+// The confidence of all candidates adds up to 1.
+type Candidate struct {
+	Parsed     Parsed
+	Order      string
+	Rule       string
+	Confidence float64
+}
+
+// ParseAll returns all valid interpretations of text, the most likely
+// first. If the text does not contain a numeric date, there is only one
+// candidate.
+func (p *Parser) ParseAll(text string) ([]Candidate, error) {
+	var candidates []Candidate
+	var weights []float64
+
+	add := func(parsed Parsed, order dateOrder, rule string, weight float64) {
+		for _, c := range candidates {
+			if c.Parsed == parsed {
+				return
+			}
+		}
+		candidates = append(candidates, Candidate{
+			Parsed: parsed,
+			Order:  order.String(),
+			Rule:   rule,
+		})
+		weights = append(weights, weight)
+	}
+
+	ctx := p.newContext(unknown, false)
+	parsed, firstErr := ctx.parse(text)
+	chosen := ctx.dateOrder
+	if firstErr == nil {
+		add(parsed, chosen, ctx.orderRule, 3)
+		if chosen == unknownOrder {
+			candidates[0].Confidence = 1
+			return candidates, nil
+		}
+	}
+
+	preferred := dayMonthYearOrder
+	if p.loc.MonthDayOrder {
+		preferred = monthDayYearOrder
+	}
+	for _, order := range []dateOrder{monthDayYearOrder, dayMonthYearOrder, yearMonthDayOrder} {
+		if order == chosen {
+			continue
+		}
+		ctx := p.newContext(unknown, false)
+		ctx.forced = order
+		parsed, err := ctx.parse(text)
+		if err != nil {
+			continue
+		}
+		weight := 1.0
+		if order == preferred {
+			weight = 2
+		}
+		add(parsed, order, ruleAlternate, weight)
+	}
+
+	if len(candidates) == 0 {
+		return nil, firstErr
+	}
+
+	total := 0.0
+	for _, w := range weights {
+		total += w
+	}
+	for i := range candidates {
+		candidates[i].Confidence = weights[i] / total
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].Confidence > candidates[j].Confidence
+	})
+	return candidates, nil
+}
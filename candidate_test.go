@@ -0,0 +1,77 @@
+package ptime
+
+import (
+	"math"
+	"testing"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestParseAll(t *testing.T) {
+	type result struct {
+		parsed     Parsed
+		order      string
+		rule       string
+		confidence float64
+	}
+	tests := []struct {
+		loc  *locale.Locale
+		text string
+		want []result
+	}{
+		{locale.EnUS, "03/04/05", []result{
+			{Parsed{Month: "03", Day: "04", Year: "05", DateSep: "/"}, "month-day-year", "locale", 0.6},
+			{Parsed{Day: "03", Month: "04", Year: "05", DateSep: "/"}, "day-month-year", "alternate", 0.2},
+			{Parsed{Year: "03", Month: "04", Day: "05", DateSep: "/"}, "year-month-day", "alternate", 0.2},
+		}},
+		{locale.FrFR, "03/04/05", []result{
+			{Parsed{Day: "03", Month: "04", Year: "05", DateSep: "/"}, "day-month-year", "locale", 0.6},
+			{Parsed{Month: "03", Day: "04", Year: "05", DateSep: "/"}, "month-day-year", "alternate", 0.2},
+			{Parsed{Year: "03", Month: "04", Day: "05", DateSep: "/"}, "year-month-day", "alternate", 0.2},
+		}},
+		{locale.EnUS, "13/04/2005", []result{
+			{Parsed{Day: "13", Month: "04", Year: "2005", DateSep: "/"}, "day-month-year", "alternate", 1},
+		}},
+		{locale.EnUS, "13/04/05", []result{
+			{Parsed{Day: "13", Month: "04", Year: "05", DateSep: "/"}, "day-month-year", "alternate", 0.5},
+			{Parsed{Year: "13", Month: "04", Day: "05", DateSep: "/"}, "year-month-day", "alternate", 0.5},
+		}},
+		{locale.EnUS, "Jan 2 2006", []result{
+			{Parsed{Month: "Jan", Day: "2", Year: "2006", DateSep: " "}, "month-day-year", "locale", 1},
+		}},
+		{locale.EnUS, "2006-01-02", []result{
+			{Parsed{Year: "2006", Month: "01", Day: "02", DateSep: "-"}, "year-month-day", "separator", 1},
+		}},
+		{locale.EnUS, "3:04pm", []result{
+			{Parsed{Hour: "3", Minute: "04", Period: "PM", TimeSep: ":"}, "unknown", "", 1},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			p := NewParser(test.loc)
+			candidates, err := p.ParseAll(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if len(candidates) != len(test.want) {
+				t.Fatalf("\n have: %v \n want: %v", candidates, test.want)
+			}
+			for i, c := range candidates {
+				want := test.want[i]
+				if c.Parsed != want.parsed || c.Order != want.order || c.Rule != want.rule ||
+					math.Abs(c.Confidence-want.confidence) > 1e-9 {
+					t.Errorf("\n have: %+v \n want: %+v", c, want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseAllError(t *testing.T) {
+	p := NewParser(locale.EnUS)
+	_, err := p.ParseAll("13/14/2005")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"log"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+var (
+	cldrDir string
+	outFile string
+)
+
+func main() {
+	log.SetFlags(0)
+	flag.StringVar(&cldrDir, "d", ".", "CLDR `directory` that contains main/*.xml")
+	flag.StringVar(&outFile, "o", "", "write output to `file` instead of stdout")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: ptime-cldr [options] locale ...\n")
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(1)
+	}
+
+	var src bytes.Buffer
+	fmt.Fprintf(&src, "// Code generated by ptime-cldr. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&src, "package locale\n\n")
+
+	var names []string
+	for _, name := range flag.Args() {
+		def, err := locale.FromCLDR(cldrDir, name)
+		if err != nil {
+			log.Fatalf("error: %v: %v", name, err)
+		}
+		if _, err := locale.New(def); err != nil {
+			log.Fatalf("error: %v: %v", name, err)
+		}
+		name = strings.ReplaceAll(name, "_", "-")
+		names = append(names, name)
+		writeDef(&src, name, def)
+	}
+
+	fmt.Fprintf(&src, "func init() {\n")
+	for _, name := range names {
+		fmt.Fprintf(&src, "Register(%q, %v)\n", name, varName(name))
+	}
+	fmt.Fprintf(&src, "}\n")
+
+	out, err := format.Source(src.Bytes())
+	if err != nil {
+		log.Fatalf("error: unable to format source: %v", err)
+	}
+	if outFile == "" {
+		os.Stdout.Write(out)
+		return
+	}
+	if err := os.WriteFile(outFile, out, 0644); err != nil {
+		log.Fatalf("error: %v", err)
+	}
+}
+
+var periodNames = map[int]string{
+	locale.AM:       "AM",
+	locale.PM:       "PM",
+	locale.Noon:     "Noon",
+	locale.Midnight: "Midnight",
+}
+
+var eraNames = map[int]string{
+	locale.BC: "BC",
+	locale.AD: "AD",
+}
+
+func varName(name string) string {
+	var v strings.Builder
+	for _, part := range strings.Split(name, "-") {
+		if part == "" {
+			continue
+		}
+		v.WriteString(strings.ToUpper(part[:1]))
+		v.WriteString(part[1:])
+	}
+	return v.String()
+}
+
+func writeDef(w *bytes.Buffer, name string, def locale.Def) {
+	fmt.Fprintf(w, "var %v = MustNew(Def{\n", varName(name))
+	if def.MonthDayOrder {
+		fmt.Fprintf(w, "MonthDayOrder: true,\n")
+	}
+	writeStrings(w, "MonthNamesWide", def.MonthNamesWide)
+	writeStrings(w, "MonthNamesAbbr", def.MonthNamesAbbr)
+	writeStrings(w, "MonthNamesNarrow", def.MonthNamesNarrow)
+	writeStrings(w, "DayNamesWide", def.DayNamesWide)
+	writeStrings(w, "DayNamesAbbr", def.DayNamesAbbr)
+	writeStrings(w, "DayNamesShort", def.DayNamesShort)
+	writeStrings(w, "DayNamesNarrow", def.DayNamesNarrow)
+	writeString2D(w, "PeriodNamesAbbr", def.PeriodNamesAbbr, periodNames)
+	writeString2D(w, "PeriodNamesNarrow", def.PeriodNamesNarrow, periodNames)
+	writeString2D(w, "EraNames", def.EraNames, eraNames)
+	writeStringMap(w, "ZoneNamesShort", def.ZoneNamesShort)
+	writeStrings(w, "DateSep", def.DateSep)
+	writeStrings(w, "TimeSep", def.TimeSep)
+	writeStrings(w, "HourSep", def.HourSep)
+	fmt.Fprintf(w, "DecimalSep: %q,\n", def.DecimalSep)
+	writeStrings(w, "DateTimeSep", def.DateTimeSep)
+	writeStrings(w, "UTCFlags", def.UTCFlags)
+	writeStrings(w, "RangeSep", def.RangeSep)
+	if def.NumberingSystem != "" {
+		fmt.Fprintf(w, "NumberingSystem: %q,\n", def.NumberingSystem)
+	}
+	fmt.Fprintf(w, "})\n\n")
+}
+
+func writeStrings(w *bytes.Buffer, field string, vals []string) {
+	if len(vals) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%v: %v,\n", field, stringList(vals))
+}
+
+func writeString2D(w *bytes.Buffer, field string, vals locale.String2D, names map[int]string) {
+	if len(vals) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "%v: String2D{\n", field)
+	for i, v := range vals {
+		if len(v) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "%v: %v,\n", names[i], stringList(v))
+	}
+	fmt.Fprintf(w, "},\n")
+}
+
+func writeStringMap(w *bytes.Buffer, field string, vals map[string]string) {
+	if len(vals) == 0 {
+		return
+	}
+	var keys []string
+	for k := range vals {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Fprintf(w, "%v: map[string]string{\n", field)
+	for _, k := range keys {
+		fmt.Fprintf(w, "%q: %q,\n", k, vals[k])
+	}
+	fmt.Fprintf(w, "},\n")
+}
+
+func stringList(vals []string) string {
+	quoted := make([]string, len(vals))
+	for i, v := range vals {
+		quoted[i] = fmt.Sprintf("%q", v)
+	}
+	return "[]string{" + strings.Join(quoted, ", ") + "}"
+}
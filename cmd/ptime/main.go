@@ -9,12 +9,16 @@ import (
 	"time"
 
 	"github.com/blackchip-org/ptime"
+	"github.com/blackchip-org/ptime/locale"
 )
 
 var (
 	dateOnly   bool
 	format     string
+	iso        bool
+	localeDir  string
 	localeName string
+	rfc        bool
 	timeOnly   bool
 	verbose    bool
 )
@@ -23,13 +27,21 @@ func main() {
 	log.SetFlags(0)
 	flag.BoolVar(&dateOnly, "d", false, "only parse date")
 	flag.StringVar(&format, "f", "", "format the result with `layout`")
+	flag.BoolVar(&iso, "i", false, "strict ISO 8601")
 	flag.StringVar(&localeName, "l", "en-US", "set `locale`")
+	flag.StringVar(&localeDir, "L", "", "load JSON locales from `directory`")
+	flag.BoolVar(&rfc, "r", false, "recognize RFC 3339, 2822, and 1123 dates")
 	flag.BoolVar(&timeOnly, "t", false, "only parse time")
 	flag.BoolVar(&verbose, "v", false, "verbose")
 
 	flag.Parse()
 
 	text := strings.Join(flag.Args(), " ")
+	if localeDir != "" {
+		if _, err := locale.LoadDir(localeDir); err != nil {
+			log.Fatalf("error: %v", err)
+		}
+	}
 	p, err := ptime.ForLocale(localeName)
 	if err != nil {
 		log.Fatal(err)
@@ -38,6 +50,8 @@ func main() {
 	if verbose {
 		p.Parser.Trace = true
 	}
+	p.Parser.ISO8601 = iso
+	p.Parser.RFC = rfc
 
 	var parseFn func(string) (ptime.Parsed, error)
 	switch {
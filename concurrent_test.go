@@ -0,0 +1,138 @@
+package ptime
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// These tests are most useful when run with the race detector:
+//
+//	go test -race -run Concurrent
+
+func TestParserConcurrent(t *testing.T) {
+	tests := []struct {
+		fn   string
+		text string
+	}{
+		{"parse", "Mon Jan 2 2006 3:04:05pm MST"},
+		{"parse", "2006-01-02T15:04:05Z"},
+		{"parse", "next Friday"},
+		{"parse", "in 3 days"},
+		{"parse", "3pm America/New_York"},
+		{"parse", "2 Jan 2006 15:04"},
+		{"date", "1/2/2006"},
+		{"date", "2006-002"},
+		{"date", "Jan 2"},
+		{"time", "15:04:05.999"},
+		{"time", "3:04pm EST"},
+		{"time", "23:59 -0700"},
+	}
+
+	p := For(locale.EnUS)
+	call := func(fn string, text string) (Parsed, error) {
+		switch fn {
+		case "date":
+			return p.ParseDate(text)
+		case "time":
+			return p.ParseTime(text)
+		}
+		return p.Parse(text)
+	}
+
+	want := make([]Parsed, len(tests))
+	for i, test := range tests {
+		parsed, err := call(test.fn, test.text)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", test.text, err)
+		}
+		want[i] = parsed
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				i := (g + n) % len(tests)
+				have, err := call(tests[i].fn, tests[i].text)
+				if err != nil {
+					t.Errorf("%v: unexpected error: %v", tests[i].text, err)
+					return
+				}
+				if have != want[i] {
+					t.Errorf("%v:\n have: %v \n want: %v", tests[i].text, have, want[i])
+					return
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+}
+
+func TestParseAllConcurrent(t *testing.T) {
+	p := For(locale.EnUS)
+	want, err := p.ParseAll("03/04/05")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				have, err := p.ParseAll("03/04/05")
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if len(have) != len(want) || have[0] != want[0] {
+					t.Errorf("\n have: %v \n want: %v", have, want)
+					return
+				}
+				// Errors must not leave state behind for the next call
+				if _, err := p.Parse("2006-13-01"); err == nil {
+					t.Errorf("expected error")
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestTimeConcurrent(t *testing.T) {
+	p := For(locale.EnUS)
+	now := time.Date(2006, 01, 02, 15, 04, 05, 0, time.UTC)
+	want := time.Date(2006, 01, 06, 0, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for n := 0; n < 50; n++ {
+				parsed, err := p.Parse("next Friday")
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				have, err := p.Time(parsed, now)
+				if err != nil {
+					t.Errorf("unexpected error: %v", err)
+					return
+				}
+				if !have.Equal(want) {
+					t.Errorf("\n have: %v \n want: %v", have, want)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,30 @@
+package ptime
+
+// Detail is the result of ParseDetail. Fields has the token from the text
+// for each field in Parsed that was set, keyed by the name of the field. A
+// field that uses more than one token, such as the offset in "-07:00" or the
+// day in "1st", has a single token that covers all of them. Separators that
+// are implied by a space are not in Fields. Discarded has the tokens that
+// were ignored by the parser.
+//
+// When the text is parsed as an RFC or ISO 8601 format there are no tokens
+// and only Parsed is set.
+type Detail struct {
+	Parsed    Parsed
+	Fields    map[string]Token
+	Discarded []Token
+}
+
+// ParseDetail parses text like Parse and also returns where each field was
+// found in the text. If there is an error, the detail has the fields that
+// were parsed before the error was found.
+func (p *Parser) ParseDetail(text string) (Detail, error) {
+	ctx := p.newContext(unknown, false)
+	ctx.fields = make(map[string]Token)
+	parsed, err := ctx.parse(text)
+	return Detail{
+		Parsed:    parsed,
+		Fields:    ctx.fields,
+		Discarded: ctx.discarded,
+	}, err
+}
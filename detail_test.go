@@ -0,0 +1,135 @@
+package ptime
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestParseDetail(t *testing.T) {
+	tests := []struct {
+		loc       *locale.Locale
+		text      string
+		fields    map[string]Token
+		discarded []Token
+	}{
+		{locale.EnUS, "Mon Jan 2 2006 3:04:05.123 pm MST", map[string]Token{
+			"Weekday":    {Text, "Mon", 1},
+			"Month":      {Text, "Jan", 5},
+			"Day":        {Number, "2", 9},
+			"Year":       {Number, "2006", 11},
+			"Hour":       {Number, "3", 16},
+			"TimeSep":    {Indicator, ":", 17},
+			"Minute":     {Number, "04", 18},
+			"Second":     {Number, "05", 21},
+			"FracSecond": {Number, "123", 24},
+			"Period":     {Text, "pm", 28},
+			"Zone":       {Text, "MST", 31},
+			"Offset":     {Text, "MST", 31},
+		}, nil},
+		{locale.EnUS, "1/2/2006 15:04 -07:00", map[string]Token{
+			"Month":   {Number, "1", 1},
+			"DateSep": {Indicator, "/", 2},
+			"Day":     {Number, "2", 3},
+			"Year":    {Number, "2006", 5},
+			"Hour":    {Number, "15", 10},
+			"TimeSep": {Indicator, ":", 12},
+			"Minute":  {Number, "04", 13},
+			"Offset":  {Indicator, "-07:00", 16},
+		}, nil},
+		{locale.EnUS, "March 1st, 44 B.C.", map[string]Token{
+			"Month": {Text, "March", 1},
+			"Day":   {Number, "1st", 7},
+			"Year":  {Number, "44", 12},
+			"Era":   {Text, "B.C.", 15},
+		}, []Token{
+			{Indicator, ",", 10},
+		}},
+		{locale.EnUS, "２０２３-01-02 10:00", map[string]Token{
+			"Year":    {Number, "２０２３", 1},
+			"DateSep": {Indicator, "-", 13},
+			"Month":   {Number, "01", 14},
+			"Day":     {Number, "02", 17},
+			"Hour":    {Number, "10", 20},
+			"TimeSep": {Indicator, ":", 22},
+			"Minute":  {Number, "00", 23},
+		}, nil},
+		{locale.EnUS, "-0043-03-15", map[string]Token{
+			"Year":    {Indicator, "-0043", 1},
+			"DateSep": {Indicator, "-", 6},
+			"Month":   {Number, "03", 7},
+			"Day":     {Number, "15", 10},
+		}, nil},
+		{locale.EnUS, "3 days ago", map[string]Token{
+			"Relative":     {Number, "3 days ago", 1},
+			"RelativeUnit": {Text, "days", 3},
+		}, nil},
+		{locale.EnUS, "tomorrow 10am", map[string]Token{
+			"Relative":     {Text, "tomorrow", 1},
+			"RelativeUnit": {Text, "tomorrow", 1},
+			"Hour":         {Number, "10", 10},
+			"Period":       {Text, "am", 12},
+		}, nil},
+		{locale.EnUS, "10:30 pm XYZ", map[string]Token{
+			"Hour":    {Number, "10", 1},
+			"TimeSep": {Indicator, ":", 3},
+			"Minute":  {Number, "30", 4},
+			"Period":  {Text, "pm", 7},
+		}, []Token{
+			{Text, "XYZ", 10},
+		}},
+		{locale.EnUS, "Jan 2 2006 ~ 15:04", map[string]Token{
+			"Month":   {Text, "Jan", 1},
+			"Day":     {Number, "2", 5},
+			"Year":    {Number, "2006", 7},
+			"Hour":    {Number, "15", 14},
+			"TimeSep": {Indicator, ":", 16},
+			"Minute":  {Number, "04", 17},
+		}, []Token{
+			{Indicator, "~", 12},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			p := NewParser(test.loc)
+			detail, err := p.ParseDetail(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			parsed, _ := p.Parse(test.text)
+			if detail.Parsed != parsed {
+				t.Errorf("\n have: %v \n want: %v", detail.Parsed, parsed)
+			}
+			if !reflect.DeepEqual(detail.Fields, test.fields) {
+				t.Errorf("\n have: %v \n want: %v", detail.Fields, test.fields)
+			}
+			if !reflect.DeepEqual(detail.Discarded, test.discarded) {
+				t.Errorf("\n have: %v \n want: %v", detail.Discarded, test.discarded)
+			}
+			for field, tok := range detail.Fields {
+				if test.text[tok.Pos-1:tok.End()-1] != tok.Val {
+					t.Errorf("%v: token %v is not in the text", field, tok)
+				}
+			}
+		})
+	}
+}
+
+func TestParseDetailError(t *testing.T) {
+	p := NewParser(locale.EnUS)
+	detail, err := p.ParseDetail("Jan 2 25:00")
+	if err == nil {
+		t.Fatalf("expected error")
+	}
+	want := map[string]Token{
+		"Month":   {Text, "Jan", 1},
+		"Day":     {Number, "2", 5},
+		"Hour":    {Number, "25", 7},
+		"TimeSep": {Indicator, ":", 9},
+	}
+	if !reflect.DeepEqual(detail.Fields, want) {
+		t.Errorf("\n have: %v \n want: %v", detail.Fields, want)
+	}
+}
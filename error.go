@@ -0,0 +1,105 @@
+package ptime
+
+import (
+	"fmt"
+	"time"
+)
+
+type ErrorCode int
+
+const (
+	ErrUnexpectedText ErrorCode = iota
+	ErrExtraNumber
+	ErrInvalidDate
+	ErrInvalidYear
+	ErrInvalidMonth
+	ErrInvalidDay
+	ErrInvalidHour
+	ErrInvalidMinute
+	ErrInvalidSecond
+	ErrInvalidOffset
+	ErrOffsetMismatch
+	ErrZoneMismatch
+	ErrInvalidZone
+	ErrInvalidRelative
+	ErrInvalidRange
+)
+
+func (e ErrorCode) String() string {
+	switch e {
+	case ErrUnexpectedText:
+		return "unexpected-text"
+	case ErrExtraNumber:
+		return "extra-number"
+	case ErrInvalidDate:
+		return "invalid-date"
+	case ErrInvalidYear:
+		return "invalid-year"
+	case ErrInvalidMonth:
+		return "invalid-month"
+	case ErrInvalidDay:
+		return "invalid-day"
+	case ErrInvalidHour:
+		return "invalid-hour"
+	case ErrInvalidMinute:
+		return "invalid-minute"
+	case ErrInvalidSecond:
+		return "invalid-second"
+	case ErrInvalidOffset:
+		return "invalid-offset"
+	case ErrOffsetMismatch:
+		return "offset-mismatch"
+	case ErrZoneMismatch:
+		return "zone-mismatch"
+	case ErrInvalidZone:
+		return "invalid-zone"
+	case ErrInvalidRelative:
+		return "invalid-relative"
+	case ErrInvalidRange:
+		return "invalid-range"
+	}
+	return "invalid"
+}
+
+// ParseError is returned by the parser when the input cannot be parsed.
+// Token is the token being parsed when the error occurred and Field is the
+// name of the field in Parsed, if any, that was being set. When the error
+// is found at the end of the input, the token type is End and the position
+// is one past the last character.
+type ParseError struct {
+	Code  ErrorCode
+	Field string
+	Token Token
+	State string
+	Msg   string
+}
+
+func (e *ParseError) Error() string {
+	return e.Msg
+}
+
+// DateError is returned when the resolved date does not exist in the
+// calendar, such as February 30 or the 366th day of a year that is not a
+// leap year. Field is the name of the field in Parsed that is not valid.
+type DateError struct {
+	Field string
+	Year  int
+	Month int
+	Day   int
+	Msg   string
+}
+
+func (e *DateError) Error() string {
+	return e.Msg
+}
+
+// WeekdayError is returned when the weekday that was parsed is not the
+// weekday of the resolved date.
+type WeekdayError struct {
+	Weekday string
+	Date    time.Time
+}
+
+func (e *WeekdayError) Error() string {
+	return fmt.Sprintf("weekday '%v' does not match date %v, which is a %v", e.Weekday, e.Date.Format("2006-01-02"), e.Date.Weekday())
+}
@@ -5,18 +5,24 @@ import (
 	"strconv"
 	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/blackchip-org/ptime/locale"
 )
 
-var formatTable = map[string]func(*locale.Locale, string, time.Time) string{
+type formatFunc func([]byte, *locale.Locale, string, time.Time) []byte
+
+var formatTable = map[string]formatFunc{
 	"weekday":     formatWeekday,
 	"year":        formatYear,
+	"era":         formatEra,
+	"week":        formatWeek,
 	"month":       formatMonth,
 	"day":         formatDay,
 	"hour":        formatHour,
 	"minute":      formatMinute,
 	"second":      formatSecond,
+	"fraction":    formatFraction,
 	"period":      formatPeriod,
 	"zone":        formatZone,
 	"offset":      formatOffset,
@@ -29,151 +35,450 @@ const (
 	badFormat = "!(BADFORMAT)"
 )
 
-func Format(loc *locale.Locale, layout string, t time.Time) string {
-	src := []rune(layout)
-	i := 0
-	var result strings.Builder
-
-	scanFormat := func() string {
-		var format strings.Builder
-		i++
-		for i < len(layout) {
-			if src[i] == ']' {
-				return format.String()
-			}
-			format.WriteRune(src[i])
-			i++
+// FormatError is returned when a layout cannot be compiled. Column is the
+// position of the field in the layout, counting from one.
+type FormatError struct {
+	Column int
+	Field  string
+	Msg    string
+}
+
+func (e *FormatError) Error() string {
+	return fmt.Sprintf("column %v: %v", e.Column, e.Msg)
+}
+
+// Layout is a layout that has been checked and prepared for formatting.
+// It can be used by multiple goroutines at the same time.
+type Layout struct {
+	loc   *locale.Locale
+	elems []layoutElem
+}
+
+type layoutElem struct {
+	literal string
+	fn      formatFunc
+	format  string
+}
+
+// CompileFormat checks the layout for fields and formats that are not
+// valid for the locale and prepares it for formatting.
+func CompileFormat(loc *locale.Locale, layout string) (*Layout, error) {
+	return compileFormat(loc, layout, true)
+}
+
+// layoutItem is either literal text or a field found in a layout
+type layoutItem struct {
+	literal string
+	field   bool
+	name    string
+	format  string
+	column  int
+}
+
+// parseLayout splits a layout into literal text and fields. Use "[[" and
+// "]]" for a literal bracket and "['text']" for literal text that is
+// output as-is, where two single quotes in a row are a literal quote. If
+// strict is false, errors are written into the literal text instead of
+// being returned.
+func parseLayout(layout string, strict bool) ([]layoutItem, error) {
+	var items []layoutItem
+	var literal strings.Builder
+	addLiteral := func() {
+		if literal.Len() > 0 {
+			items = append(items, layoutItem{literal: literal.String()})
+			literal.Reset()
 		}
-		return format.String()
 	}
 
-	scanField := func() (string, string) {
-		var name strings.Builder
-		var format string
-
-		i++
-		for i < len(layout) {
-			if src[i] == '/' {
-				format = scanFormat()
+	for i := 0; i < len(layout); i++ {
+		ch := layout[i]
+		column := utf8.RuneCountInString(layout[:i]) + 1
+		next := byte(0)
+		if i+1 < len(layout) {
+			next = layout[i+1]
+		}
+		switch {
+		case ch == '[' && next == '[', ch == ']' && next == ']':
+			literal.WriteByte(ch)
+			i++
+		case ch == ']':
+			if strict {
+				return nil, &FormatError{Column: column, Msg: "unexpected ']', use ']]' for a literal bracket"}
+			}
+			literal.WriteByte(ch)
+		case ch == '[' && next == '\'':
+			text, n, ok := scanQuoted(layout[i+1:])
+			if !ok {
+				if strict {
+					return nil, &FormatError{Column: column, Msg: "missing closing quote and ']'"}
+				}
+				literal.WriteString(badField)
+				i = len(layout)
 				break
 			}
-			if src[i] == ']' {
+			literal.WriteString(text)
+			i += n
+		case ch == '[':
+			end := strings.IndexByte(layout[i:], ']')
+			if end < 0 {
+				if strict {
+					return nil, &FormatError{Column: column, Msg: "missing ']'"}
+				}
+				literal.WriteString(badField)
+				i = len(layout)
 				break
 			}
-			name.WriteRune(src[i])
+			name, format, _ := strings.Cut(layout[i+1:i+end], "/")
+			i += end
+			addLiteral()
+			items = append(items, layoutItem{field: true, name: name, format: format, column: column})
+		default:
+			literal.WriteByte(ch)
+		}
+	}
+	addLiteral()
+	return items, nil
+}
+
+// scanQuoted reads quoted text, such as "'at']", that follows a '['.
+// Returns the text, the number of bytes read including the closing ']',
+// and false if the quote or bracket is missing.
+func scanQuoted(src string) (string, int, bool) {
+	var text strings.Builder
+	for i := 1; i < len(src); i++ {
+		if src[i] != '\'' {
+			text.WriteByte(src[i])
+			continue
+		}
+		if i+1 < len(src) && src[i+1] == '\'' {
+			text.WriteByte('\'')
 			i++
+			continue
 		}
-		if src[i] != ']' {
-			return "", ""
+		if i+1 < len(src) && src[i+1] == ']' {
+			return text.String(), i + 2, true
 		}
-		return name.String(), format
+		return "", 0, false
 	}
+	return "", 0, false
+}
 
-	for i < len(layout) {
-		if src[i] == '[' {
-			name, format := scanField()
-			fn, ok := formatTable[name]
-			if !ok {
-				result.WriteString(badField)
-			} else {
-				result.WriteString(fn(loc, format, t))
+// compileFormat prepares the layout for formatting. If strict is false,
+// errors are written into the output instead of being returned.
+func compileFormat(loc *locale.Locale, layout string, strict bool) (*Layout, error) {
+	items, err := parseLayout(layout, strict)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &Layout{loc: loc}
+	var literal strings.Builder
+	addLiteral := func() {
+		if literal.Len() > 0 {
+			l.elems = append(l.elems, layoutElem{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+	for _, item := range items {
+		if !item.field {
+			literal.WriteString(item.literal)
+			continue
+		}
+		fn, ok := formatTable[item.name]
+		if !ok {
+			if strict {
+				return nil, &FormatError{Column: item.column, Field: item.name, Msg: fmt.Sprintf("unknown field: %v", item.name)}
 			}
+			literal.WriteString(badField)
+			continue
+		}
+		if strict && strings.Contains(string(fn(nil, loc, item.format, time.Time{})), badFormat) {
+			return nil, &FormatError{Column: item.column, Field: item.name, Msg: fmt.Sprintf("invalid format for %v: %v", item.name, item.format)}
+		}
+		addLiteral()
+		l.elems = append(l.elems, layoutElem{fn: fn, format: item.format})
+	}
+	addLiteral()
+	return l, nil
+}
+
+// Format returns the time formatted with the layout
+func (l *Layout) Format(t time.Time) string {
+	return string(l.AppendFormat(nil, t))
+}
+
+// AppendFormat is like Format but appends the result to buf. It does not
+// allocate if buf has enough room for the result.
+func (l *Layout) AppendFormat(buf []byte, t time.Time) []byte {
+	start := len(buf)
+	zero := l.loc.Zero()
+	for _, e := range l.elems {
+		if e.fn == nil {
+			buf = append(buf, e.literal...)
+		} else if zero != '0' {
+			n := len(buf)
+			buf = nativeDigits(e.fn(buf, l.loc, e.format, t), n, zero)
 		} else {
-			result.WriteRune(src[i])
+			buf = e.fn(buf, l.loc, e.format, t)
+		}
+	}
+
+	// Remove leading and trailing whitespace from the formatted result
+	end := len(buf)
+	for end > start && isSpace(buf[end-1]) {
+		end--
+	}
+	lead := start
+	for lead < end && isSpace(buf[lead]) {
+		lead++
+	}
+	n := copy(buf[start:], buf[lead:end])
+	return buf[:start+n]
+}
+
+func Format(loc *locale.Locale, layout string, t time.Time) string {
+	l, _ := compileFormat(loc, layout, false)
+	return l.Format(t)
+}
+
+// nativeDigits replaces the ASCII digits in b[start:] with the digits of
+// the numbering system that starts at zero. The bytes are moved from the
+// end so that it can be done in place.
+func nativeDigits(b []byte, start int, zero rune) []byte {
+	digits := 0
+	for _, ch := range b[start:] {
+		if ch >= '0' && ch <= '9' {
+			digits++
 		}
-		i++
 	}
-	return strings.TrimSpace(result.String())
+	if digits == 0 {
+		return b
+	}
+	w := utf8.RuneLen(zero)
+	end := len(b)
+	b = append(b, make([]byte, digits*(w-1))...)
+	j := len(b)
+	for i := end - 1; i >= start; i-- {
+		ch := b[i]
+		if ch < '0' || ch > '9' {
+			j--
+			b[j] = ch
+			continue
+		}
+		j -= w
+		utf8.EncodeRune(b[j:], zero+rune(ch-'0'))
+	}
+	return b
 }
 
-func formatWeekday(loc *locale.Locale, format string, t time.Time) string {
+func isSpace(ch byte) bool {
+	return ch == ' ' || ch == '\t' || ch == '\n' || ch == '\r'
+}
+
+// appendInt appends n padded to width with the pad character. Use a pad of
+// zero for no padding.
+func appendInt(b []byte, n int, width int, pad byte) []byte {
+	if n < 0 {
+		b = append(b, '-')
+		n = -n
+	}
+	if pad != 0 {
+		digits := 1
+		for v := n; v >= 10; v /= 10 {
+			digits++
+		}
+		for ; digits < width; digits++ {
+			b = append(b, pad)
+		}
+	}
+	return strconv.AppendInt(b, int64(n), 10)
+}
+
+func formatWeekday(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "", "wide":
-		return loc.DayNamesWide[t.Weekday()]
+		return append(b, loc.DayNamesWide[t.Weekday()]...)
 	case "abbr":
-		return loc.DayNamesAbbr[t.Weekday()]
+		return append(b, loc.DayNamesAbbr[t.Weekday()]...)
+	case "short":
+		if len(loc.DayNamesShort) == 0 {
+			return append(b, loc.DayNamesAbbr[t.Weekday()]...)
+		}
+		return append(b, loc.DayNamesShort[t.Weekday()]...)
+	case "narrow":
+		if len(loc.DayNamesNarrow) == 0 {
+			return append(b, badFormat...)
+		}
+		return append(b, loc.DayNamesNarrow[t.Weekday()]...)
+	case "iso":
+		return appendInt(b, (int(t.Weekday())+6)%7+1, 0, 0)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatYear(loc *locale.Locale, format string, t time.Time) string {
+func formatYear(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "":
-		return fmt.Sprintf("%04d", t.Year())
+		return appendInt(b, t.Year(), 4, '0')
 	case "2":
-		return fmt.Sprintf("%02d", t.Year()%100)
+		return appendInt(b, t.Year()%100, 2, '0')
+	case "week":
+		year, _ := t.ISOWeek()
+		return appendInt(b, year, 4, '0')
+	case "era":
+		year := t.Year()
+		if year < 1 {
+			year = 1 - year
+		}
+		return appendInt(b, year, 0, 0)
+	case "full":
+		return appendInt(b, t.Year(), 0, 0)
+	case "iso":
+		// Years outside of 0000 to 9999 need a sign
+		if t.Year() > 9999 {
+			b = append(b, '+')
+		}
+		return appendInt(b, t.Year(), 4, '0')
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatMonth(loc *locale.Locale, format string, t time.Time) string {
+func formatEra(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	era := locale.AD
+	if t.Year() < 1 {
+		era = locale.BC
+	}
+	var name string
+	switch format {
+	case "", "abbr":
+		name = loc.EraNames.Main(era)
+	case "alt":
+		name = loc.EraNames.Alt(era)
+	}
+	if name == "" {
+		return append(b, badFormat...)
+	}
+	return append(b, name...)
+}
+
+func formatWeek(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	_, week := t.ISOWeek()
 	switch format {
 	case "":
-		return strconv.Itoa(int(t.Month()))
+		return appendInt(b, week, 0, 0)
+	case "02":
+		return appendInt(b, week, 2, '0')
+	}
+	return append(b, badFormat...)
+}
+
+func formatMonth(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	switch format {
+	case "":
+		return appendInt(b, int(t.Month()), 0, 0)
 	case "2":
-		return fmt.Sprintf("%2d", int(t.Month()))
+		return appendInt(b, int(t.Month()), 2, ' ')
 	case "02":
-		return fmt.Sprintf("%02d", int(t.Month()))
+		return appendInt(b, int(t.Month()), 2, '0')
 	case "abbr":
-		return loc.MonthNamesAbbr[int(t.Month())-1]
+		return append(b, loc.MonthNamesAbbr[int(t.Month())-1]...)
 	case "wide", "name":
-		return loc.MonthNamesWide[int(t.Month())-1]
+		return append(b, loc.MonthNamesWide[int(t.Month())-1]...)
+	case "narrow":
+		if len(loc.MonthNamesNarrow) == 0 {
+			return append(b, badFormat...)
+		}
+		return append(b, loc.MonthNamesNarrow[int(t.Month())-1]...)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatDay(loc *locale.Locale, format string, t time.Time) string {
+func formatDay(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "":
-		return strconv.Itoa(t.Day())
+		return appendInt(b, t.Day(), 0, 0)
 	case "2":
-		return fmt.Sprintf("%2d", t.Day())
+		return appendInt(b, t.Day(), 2, ' ')
 	case "02":
-		return fmt.Sprintf("%02d", t.Day())
+		return appendInt(b, t.Day(), 2, '0')
 	case "year":
-		return fmt.Sprintf("%03d", t.YearDay())
+		return appendInt(b, t.YearDay(), 3, '0')
+	case "ordinal":
+		suffix, ok := loc.OrdinalSuffix(t.Day())
+		if !ok {
+			break
+		}
+		return append(appendInt(b, t.Day(), 0, 0), suffix...)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatHour(loc *locale.Locale, format string, t time.Time) string {
+func formatHour(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "", "24":
-		return strconv.Itoa(t.Hour())
-	case "12":
-		h := t.Hour()
-		if h > 12 {
-			h = h - 12
+		return appendInt(b, t.Hour(), 0, 0)
+	case "02":
+		return appendInt(b, t.Hour(), 2, '0')
+	case "12", "12-2", "12-02":
+		h := t.Hour() % 12
+		if h == 0 {
+			h = 12
 		}
-		return strconv.Itoa(h)
+		switch format {
+		case "12-2":
+			return appendInt(b, h, 2, ' ')
+		case "12-02":
+			return appendInt(b, h, 2, '0')
+		}
+		return appendInt(b, h, 0, 0)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatMinute(loc *locale.Locale, format string, t time.Time) string {
+func formatMinute(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "":
-		return fmt.Sprintf("%02d", t.Minute())
+		return appendInt(b, t.Minute(), 2, '0')
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatSecond(loc *locale.Locale, format string, t time.Time) string {
+func formatSecond(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "", "0":
-		return fmt.Sprintf("%02d", t.Second())
+		return appendInt(b, t.Second(), 2, '0')
 	}
 
-	_, err := strconv.Atoi(format)
-	if err != nil {
-		return badFormat
+	digits, err := strconv.Atoi(format)
+	if err != nil || digits < 0 || digits > 9 {
+		return append(b, badFormat...)
+	}
+	b = appendInt(b, t.Second(), 2, '0')
+	b = append(b, '.')
+	return appendFraction(b, t.Nanosecond(), digits)
+}
+
+func formatFraction(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	digits := 9
+	if format != "" {
+		n, err := strconv.Atoi(format)
+		if err != nil || n < 1 || n > 9 {
+			return append(b, badFormat...)
+		}
+		digits = n
 	}
-	s := float64(t.Second()) + (float64(t.Nanosecond()) * 1e-9)
-	spec := "%." + format + "f"
-	return fmt.Sprintf(spec, s)
+	return appendFraction(b, t.Nanosecond(), digits)
 }
 
-func formatPeriod(loc *locale.Locale, format string, t time.Time) string {
+// appendFraction appends the first digits of the nanoseconds
+func appendFraction(b []byte, nsec int, digits int) []byte {
+	for i := 9; i > digits; i-- {
+		nsec /= 10
+	}
+	return appendInt(b, nsec, digits, '0')
+}
+
+func formatPeriod(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	period := locale.AM
 	if t.Hour() >= 12 {
 		period = locale.PM
@@ -181,50 +486,97 @@ func formatPeriod(loc *locale.Locale, format string, t time.Time) string {
 
 	switch format {
 	case "", "abbr":
-		return loc.PeriodNamesAbbr.Main(period)
+		return append(b, loc.PeriodNamesAbbr.Main(period)...)
 	case "alt", "abbr-alt":
-		return loc.PeriodNamesAbbr.Alt(period)
+		return append(b, loc.PeriodNamesAbbr.Alt(period)...)
 	case "narrow":
-		return loc.PeriodNamesNarrow.Main(period)
+		return append(b, loc.PeriodNamesNarrow.Main(period)...)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatZone(loc *locale.Locale, format string, t time.Time) string {
-	zone, _ := t.Zone()
+func formatZone(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	switch format {
 	case "":
-		return zone
+		zone, _ := t.Zone()
+		return append(b, zone...)
+	case "location":
+		return append(b, t.Location().String()...)
 	}
-	return badFormat
+	return append(b, badFormat...)
 }
 
-func formatOffset(loc *locale.Locale, format string, t time.Time) string {
+func formatOffset(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
 	_, offset := t.Zone()
 
 	switch format {
 	case "":
-		return FormatOffset(offset, "")
+		return appendOffset(b, offset, "")
 	case ":":
-		return FormatOffset(offset, ":")
+		return appendOffset(b, offset, ":")
+	case "Z", "Z:":
+		if offset == 0 {
+			return append(b, 'Z')
+		}
+		return appendOffset(b, offset, format[1:])
+	}
+	return append(b, badFormat...)
+}
+
+func formatZoneOffset(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	zone, offset := t.Zone()
+	b = append(b, zone...)
+	if isUTC(loc, zone, offset) {
+		return b
+	}
+	b = append(b, ' ')
+	return formatOffset(b, loc, format, t)
+}
+
+func formatOffsetZone(b []byte, loc *locale.Locale, format string, t time.Time) []byte {
+	zone, offset := t.Zone()
+	if isUTC(loc, zone, offset) {
+		return append(b, zone...)
 	}
-	return badFormat
+	b = formatOffset(b, loc, format, t)
+	b = append(b, ' ')
+	return append(b, zone...)
 }
 
-func formatZoneOffset(loc *locale.Locale, format string, t time.Time) string {
-	zone := formatZone(loc, "", t)
-	offset := formatOffset(loc, format, t)
-	if o, ok := loc.Offsets[loc.Key(zone)]; ok && o == 0 {
-		return zone
+// isUTC checks if the zone is one of the names for UTC in the locale. Zone
+// names from the time package are short and in ASCII so the key is built on
+// the stack to keep formatting from allocating. Other names use Key.
+func isUTC(loc *locale.Locale, zone string, offset int) bool {
+	if offset != 0 {
+		return false
+	}
+	var buf [16]byte
+	key := buf[:0]
+	for i := 0; i < len(zone); i++ {
+		ch := zone[i]
+		switch {
+		case ch >= utf8.RuneSelf || ch == '\t' || len(key) == len(buf):
+			o, ok := loc.Offsets[loc.Key(zone)]
+			return ok && o == 0
+		case ch == '.':
+		case 'A' <= ch && ch <= 'Z':
+			key = append(key, ch+'a'-'A')
+		default:
+			key = append(key, ch)
+		}
 	}
-	return zone + " " + offset
+	o, ok := loc.Offsets[string(key)]
+	return ok && o == 0
 }
 
-func formatOffsetZone(loc *locale.Locale, format string, t time.Time) string {
-	zone := formatZone(loc, "", t)
-	offset := formatOffset(loc, format, t)
-	if o, ok := loc.Offsets[loc.Key(zone)]; ok && o == 0 {
-		return zone
+func appendOffset(b []byte, offset int, sep string) []byte {
+	if offset < 0 {
+		b = append(b, '-')
+		offset = -offset
+	} else {
+		b = append(b, '+')
 	}
-	return offset + " " + zone
+	b = appendInt(b, offset/3600, 2, '0')
+	b = append(b, sep...)
+	return appendInt(b, offset/60%60, 2, '0')
 }
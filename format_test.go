@@ -1,6 +1,7 @@
 package ptime
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -23,6 +24,36 @@ func TestFormatEnUS(t *testing.T) {
 			"[year]-[day/year]",
 			"2016-327",
 		},
+		{
+			"2016-11-22",
+			"[month/wide] [day/ordinal]",
+			"November 22nd",
+		},
+		{
+			"2016-11-11",
+			"[month/wide] [day/ordinal]",
+			"November 11th",
+		},
+		{
+			"2016-11-01",
+			"[month/wide] [day/ordinal]",
+			"November 1st",
+		},
+		{
+			"March 15 44 BC",
+			"[year/era] [era] ([year/iso])",
+			"44 BC (-0043)",
+		},
+		{
+			"AD 1066",
+			"[year/era] [era/alt] [year/full]",
+			"1066 CE 1066",
+		},
+		{
+			"10000-01-02",
+			"[year/iso]-[month/02]-[day/02]",
+			"+10000-01-02",
+		},
 		{
 			"2016-05-06",
 			"[month]/[day]",
@@ -73,11 +104,21 @@ func TestFormatEnUS(t *testing.T) {
 			"[weekday/abbr], [month/abbr] [day] [year/2]",
 			"Fri, May 6 16",
 		},
+		{
+			"2016-05-06",
+			"[weekday/short] [weekday/narrow] [month/narrow]",
+			"Fr F M",
+		},
 		{
 			"17:30:25 MST -0700",
 			"[hour]:[minute]:[second] [zone-offset]",
 			"17:30:25 MST -0700",
 		},
+		{
+			"17:30:25 America/New_York",
+			"[hour]:[minute] [zone] [zone/location]",
+			"17:30 EST America/New_York",
+		},
 		{
 			"17:30:25 UTC +0000",
 			"[hour]:[minute]:[second] [zone-offset]",
@@ -114,6 +155,21 @@ func TestFormatEnUS(t *testing.T) {
 			"[hour/12]:[minute][period/narrow]",
 			"5:30p",
 		},
+		{
+			"00:15",
+			"[hour/12]:[minute][period]",
+			"12:15AM",
+		},
+		{
+			"12:15",
+			"[hour/12]:[minute][period]",
+			"12:15PM",
+		},
+		{
+			"00:15",
+			"[hour/12-02]:[minute]",
+			"12:15",
+		},
 		{
 			"17:30:25",
 			"[hour/12]:[minute][period/alt]",
@@ -129,6 +185,16 @@ func TestFormatEnUS(t *testing.T) {
 			"[hour]:[minute]:[second/2]",
 			"17:30:25.12",
 		},
+		{
+			"17:30:05.1239",
+			"[hour]:[minute]:[second/3]",
+			"17:30:05.123",
+		},
+		{
+			"17:30:59.9996",
+			"[hour]:[minute]:[second/3]",
+			"17:30:59.999",
+		},
 		{
 			"17:30:25",
 			"[hour]:[minute]:[second]",
@@ -192,6 +258,21 @@ func TestFormatFrFR(t *testing.T) {
 			"[weekday/abbr] [day]/[month]",
 			"sam. 2/1",
 		},
+		{
+			"2016-03-01",
+			"le [day/ordinal] [month/wide]",
+			"le 1er mars",
+		},
+		{
+			"2016-03-03",
+			"le [day/ordinal] [month/wide]",
+			"le 3e mars",
+		},
+		{
+			"15 mars 44 av. J.-C.",
+			"[day] [month/wide] [year/era] [era]",
+			"15 mars 44 av. J.-C.",
+		},
 	}
 
 	l := time.UTC
@@ -215,3 +296,169 @@ func TestFormatFrFR(t *testing.T) {
 		})
 	}
 }
+
+func TestCompileFormat(t *testing.T) {
+	mst := time.FixedZone("MST", -7*3600)
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 123456789, mst)
+	layouts := []string{
+		"[year]-[month/02]-[day/02] [hour]:[minute]:[second/3] [zone-offset]",
+		"[weekday], [month/wide] [day] [year] [hour/12]:[minute][period/alt]",
+		"  [day/2] [month/abbr]  ",
+		"à [hour] h [minute]",
+	}
+
+	for _, layout := range layouts {
+		t.Run(layout, func(t *testing.T) {
+			l, err := CompileFormat(locale.EnUS, layout)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := Format(locale.EnUS, layout, ts)
+			if have := l.Format(ts); have != want {
+				t.Errorf("\n have: %q \n want: %q", have, want)
+			}
+			buf := []byte("prefix:")
+			if have := string(l.AppendFormat(buf, ts)); have != "prefix:"+want {
+				t.Errorf("\n have: %q \n want: %q", have, "prefix:"+want)
+			}
+		})
+	}
+}
+
+func TestFormatEscape(t *testing.T) {
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	tests := []struct {
+		layout string
+		out    string
+	}{
+		{"[[[year]]]", "[2006]"},
+		{"[hour] ['[at]'] [minute]", "15 [at] 04"},
+		{"['it''s'] [hour]", "it's 15"},
+		{"['[day]'] [day]", "[day] 2"},
+		{"['']", ""},
+		{"[year]]", "2006]"},
+		{"[year] [month", "2006 !(BADFIELD)"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout, func(t *testing.T) {
+			out := Format(locale.EnUS, test.layout, ts)
+			if out != test.out {
+				t.Errorf("\n have: %v \n want: %v", out, test.out)
+			}
+		})
+	}
+}
+
+func TestCompileFormatError(t *testing.T) {
+	def := locale.FrFR.Def
+	def.MonthNamesNarrow = nil
+	noNarrow := locale.MustNew(def)
+
+	tests := []struct {
+		loc    *locale.Locale
+		layout string
+		column int
+		field  string
+	}{
+		{locale.EnUS, "[year]-[mnth]", 8, "mnth"},
+		{locale.EnUS, "[year]-[month/3]", 8, "month"},
+		{locale.EnUS, "[year]-[month", 8, ""},
+		{locale.EnUS, "[year]]-[month]", 7, ""},
+		{locale.EnUS, "[hour] ['at] [minute]", 8, ""},
+		{locale.EnUS, "[hour] ['at'", 8, ""},
+		{locale.EnUS, "à [second/x]", 3, "second"},
+		{noNarrow, "[day] [month/narrow]", 7, "month"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout, func(t *testing.T) {
+			_, err := CompileFormat(test.loc, test.layout)
+			var ferr *FormatError
+			if !errors.As(err, &ferr) {
+				t.Fatalf("expected format error, have: %v", err)
+			}
+			if ferr.Column != test.column || ferr.Field != test.field {
+				t.Errorf("\n have: %v %v (%v) \n want: %v %v", ferr.Column, ferr.Field, ferr, test.column, test.field)
+			}
+		})
+	}
+}
+
+func TestAppendFormatAllocs(t *testing.T) {
+	mst := time.FixedZone("MST", -7*3600)
+	tests := []struct {
+		layout string
+		t      time.Time
+		want   string
+	}{
+		{"[weekday/abbr] [year]-[month/02]-[day/02] [hour]:[minute]:[second/3] [offset/:]", time.Date(2006, 1, 2, 15, 4, 5, 123456789, mst), "Mon 2006-01-02 15:04:05.123 -07:00"},
+		{"[hour]:[minute] [zone-offset]", time.Date(2006, 1, 2, 15, 4, 0, 0, time.UTC), "15:04 UTC"},
+		{"[hour]:[minute] [offset-zone]", time.Date(2006, 1, 2, 15, 4, 0, 0, time.UTC), "15:04 UTC"},
+		{"[hour]:[minute] [zone-offset]", time.Date(2006, 1, 2, 15, 4, 0, 0, mst), "15:04 MST -0700"},
+		{"[hour]:[minute] [offset-zone]", time.Date(2006, 1, 2, 15, 4, 0, 0, mst), "15:04 -0700 MST"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout, func(t *testing.T) {
+			l, err := CompileFormat(locale.EnUS, test.layout)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			buf := make([]byte, 0, 64)
+			allocs := testing.AllocsPerRun(100, func() {
+				buf = l.AppendFormat(buf[:0], test.t)
+			})
+			if allocs != 0 {
+				t.Errorf("have %v allocations, want 0", allocs)
+			}
+			if string(buf) != test.want {
+				t.Errorf("\n have: %v \n want: %v", string(buf), test.want)
+			}
+		})
+	}
+}
+
+func BenchmarkAppendFormat(b *testing.B) {
+	l, err := CompileFormat(locale.EnUS, "[year]-[month/02]-[day/02] [hour]:[minute]:[second/3] [offset/:]")
+	if err != nil {
+		b.Fatalf("unexpected error: %v", err)
+	}
+	ts := time.Now()
+	buf := make([]byte, 0, 64)
+	for i := 0; i < b.N; i++ {
+		buf = l.AppendFormat(buf[:0], ts)
+	}
+}
+
+func TestFormatNumberingSystem(t *testing.T) {
+	tests := []struct {
+		system string
+		out    string
+	}{
+		{"arab", "٢٠٠٦-٠١-٠٢ ٣:٠٤ PM -٠٧:٠٠"},
+		{"deva", "२००६-०१-०२ ३:०४ PM -०७:००"},
+		{"fullwide", "２００６-０１-０２ ３:０４ PM -０７:００"},
+		{"latn", "2006-01-02 3:04 PM -07:00"},
+	}
+
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 0, time.FixedZone("MST", -7*3600))
+	for _, test := range tests {
+		t.Run(test.system, func(t *testing.T) {
+			def := locale.EnUS.Def
+			def.NumberingSystem = test.system
+			loc := locale.MustNew(def)
+			out := Format(loc, "[year]-[month/02]-[day/02] [hour/12]:[minute] [period] [offset/:]", ts)
+			if out != test.out {
+				t.Errorf("\n have: %v \n want: %v", out, test.out)
+			}
+			parsed, err := NewParser(loc).Parse(out)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if parsed.Year != "2006" || parsed.Hour != "3" || parsed.Offset != "-0700" {
+				t.Errorf("unexpected parse: %v", parsed)
+			}
+		})
+	}
+}
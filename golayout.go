@@ -0,0 +1,210 @@
+package ptime
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// goChunks are the elements of a Go reference layout in the order they are
+// matched. Chunks that have no equivalent field are mapped to an empty
+// string.
+var goChunks = []struct {
+	chunk string
+	field string
+}{
+	{"January", "month/wide"},
+	{"Jan", "month/abbr"},
+	{"Monday", "weekday/wide"},
+	{"Mon", "weekday/abbr"},
+	{"MST", "zone"},
+	{"2006", "year"},
+	{"002", "day/year"},
+	{"__2", ""},
+	{"_2", "day/2"},
+	{"01", "month/02"},
+	{"02", "day/02"},
+	{"03", "hour/12-02"},
+	{"04", "minute"},
+	{"05", "second"},
+	{"06", "year/2"},
+	{"15", "hour/02"},
+	{"1", "month"},
+	{"2", "day"},
+	{"3", "hour/12"},
+	{"4", ""},
+	{"5", ""},
+	{"PM", "period"},
+	{"pm", "period/alt"},
+	{"-07:00:00", ""},
+	{"-070000", ""},
+	{"-07:00", "offset/:"},
+	{"-0700", "offset"},
+	{"-07", ""},
+	{"Z07:00:00", ""},
+	{"Z070000", ""},
+	{"Z07:00", "offset/Z:"},
+	{"Z0700", "offset/Z"},
+	{"Z07", ""},
+}
+
+// goAliases are fields that have more than one name for the same Go chunk
+var goAliases = map[string]string{
+	"month/name":  "January",
+	"weekday":     "Monday",
+	"period/abbr": "PM",
+	"second/0":    "05",
+}
+
+// nextGoChunk finds the next element in a Go reference layout. It returns
+// the literal text before the element, the element, the field it maps to,
+// and the rest of the layout. If there are no more elements, the chunk is
+// empty and the prefix is the remaining layout.
+func nextGoChunk(layout string) (prefix string, chunk string, field string, suffix string) {
+	for i := 0; i < len(layout); i++ {
+		rest := layout[i:]
+		// "_2006" is an underscore followed by a year and not "_2"
+		if strings.HasPrefix(rest, "_2006") {
+			continue
+		}
+		if ch := rest[0]; ch == '.' || ch == ',' {
+			if chunk, field, ok := goFraction(rest); ok {
+				return layout[:i], chunk, field, rest[len(chunk):]
+			}
+			continue
+		}
+		for _, c := range goChunks {
+			if strings.HasPrefix(rest, c.chunk) {
+				return layout[:i], c.chunk, c.field, rest[len(c.chunk):]
+			}
+		}
+	}
+	return layout, "", "", ""
+}
+
+// goFraction checks for fractional seconds in the form of ".000" or ".999".
+// Only the fixed width form has an equivalent field.
+func goFraction(layout string) (string, string, bool) {
+	if len(layout) < 2 || (layout[1] != '0' && layout[1] != '9') {
+		return "", "", false
+	}
+	j := 1
+	for j < len(layout) && layout[j] == layout[1] {
+		j++
+	}
+	if j < len(layout) && isDigit(layout[j]) {
+		return "", "", false
+	}
+	if layout[1] == '9' {
+		return layout[:j], "", true
+	}
+	return layout[:j], fmt.Sprintf("%c[fraction/%v]", layout[0], j-1), true
+}
+
+// FromGoLayout converts a Go reference layout, such as
+// "Mon Jan 2 15:04:05 MST 2006", to a layout that can be used with Format.
+func FromGoLayout(goLayout string) (string, error) {
+	var result strings.Builder
+	for goLayout != "" {
+		prefix, chunk, field, suffix := nextGoChunk(goLayout)
+		result.WriteString(escapeLiteral(prefix))
+		if chunk == "" {
+			break
+		}
+		switch {
+		case field == "":
+			return "", fmt.Errorf("no equivalent for Go layout element: %v", chunk)
+		case strings.HasPrefix(field, ".") || strings.HasPrefix(field, ","):
+			result.WriteString(field)
+		default:
+			result.WriteString("[" + field + "]")
+		}
+		goLayout = suffix
+	}
+	return result.String(), nil
+}
+
+// escapeLiteral doubles any brackets in the text so that it can be used
+// as literal text in a layout.
+func escapeLiteral(text string) string {
+	text = strings.ReplaceAll(text, "[", "[[")
+	return strings.ReplaceAll(text, "]", "]]")
+}
+
+// ToGoLayout converts a layout used with Format to a Go reference layout.
+func ToGoLayout(layout string) (string, error) {
+	toGo := make(map[string]string)
+	for _, c := range goChunks {
+		if c.field != "" {
+			toGo[c.field] = c.chunk
+		}
+	}
+	for field, chunk := range goAliases {
+		toGo[field] = chunk
+	}
+
+	items, err := parseLayout(layout, true)
+	if err != nil {
+		return "", err
+	}
+	var result strings.Builder
+	for _, item := range items {
+		if !item.field {
+			if _, chunk, _, _ := nextGoChunk(item.literal); chunk != "" {
+				return "", fmt.Errorf("literal text would be read as a Go layout element: %v", chunk)
+			}
+			result.WriteString(item.literal)
+			continue
+		}
+		field := item.name
+		if item.format != "" {
+			field += "/" + item.format
+		}
+
+		// Fractional seconds need the separator that comes before them
+		var digits int
+		if n, _ := fmt.Sscanf(field, "fraction/%d", &digits); n == 1 {
+			out := result.String()
+			if !strings.HasSuffix(out, ".") && !strings.HasSuffix(out, ",") {
+				return "", fmt.Errorf("fraction must follow '.' or ',': %v", field)
+			}
+			result.WriteString(strings.Repeat("0", digits))
+			continue
+		}
+		chunk, ok := toGo[field]
+		if !ok {
+			return "", fmt.Errorf("no equivalent for field in Go layout: %v", field)
+		}
+		result.WriteString(chunk)
+	}
+	return result.String(), nil
+}
+
+// FormatGo formats the time using a Go reference layout. Unlike the
+// standard library, names for months, weekdays, and periods come from the
+// locale. Elements without an equivalent field are formatted as
+// "!(BADFIELD)".
+func FormatGo(loc *locale.Locale, goLayout string, t time.Time) string {
+	var b []byte
+	for goLayout != "" {
+		prefix, chunk, field, suffix := nextGoChunk(goLayout)
+		b = append(b, prefix...)
+		if chunk == "" {
+			break
+		}
+		switch {
+		case field == "":
+			b = append(b, badField...)
+		case strings.HasPrefix(field, ".") || strings.HasPrefix(field, ","):
+			b = append(b, field[0])
+			b = appendFraction(b, t.Nanosecond(), len(chunk)-1)
+		default:
+			name, format, _ := strings.Cut(field, "/")
+			b = formatTable[name](b, loc, format, t)
+		}
+		goLayout = suffix
+	}
+	return string(b)
+}
@@ -0,0 +1,95 @@
+package ptime
+
+import (
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestGoLayout(t *testing.T) {
+	tests := []struct {
+		goLayout string
+		layout   string
+	}{
+		{time.ANSIC, "[weekday/abbr] [month/abbr] [day/2] [hour/02]:[minute]:[second] [year]"},
+		{time.RFC1123Z, "[weekday/abbr], [day/02] [month/abbr] [year] [hour/02]:[minute]:[second] [offset]"},
+		{time.RFC3339, "[year]-[month/02]-[day/02]T[hour/02]:[minute]:[second][offset/Z:]"},
+		{time.Kitchen, "[hour/12]:[minute][period]"},
+		{"Monday, January 2 2006 3:04pm", "[weekday/wide], [month/wide] [day] [year] [hour/12]:[minute][period/alt]"},
+		{"2006-002 15:04:05.000 -07:00", "[year]-[day/year] [hour/02]:[minute]:[second].[fraction/3] [offset/:]"},
+		{"06/1/2 at 03:04", "[year/2]/[month]/[day] at [hour/12-02]:[minute]"},
+		{"[2006]", "[[[year]]]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.goLayout, func(t *testing.T) {
+			layout, err := FromGoLayout(test.goLayout)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if layout != test.layout {
+				t.Errorf("from go\n have: %v \n want: %v", layout, test.layout)
+			}
+			goLayout, err := ToGoLayout(test.layout)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if goLayout != test.goLayout {
+				t.Errorf("to go\n have: %v \n want: %v", goLayout, test.goLayout)
+			}
+		})
+	}
+}
+
+func TestGoLayoutError(t *testing.T) {
+	for _, goLayout := range []string{time.RFC3339Nano, "__2", "3:4:5"} {
+		if _, err := FromGoLayout(goLayout); err == nil {
+			t.Errorf("%v: expected error", goLayout)
+		}
+	}
+	for _, layout := range []string{"[hour]", "Day 1: [day]", "[second]:[fraction/3]", "[month", "[foo]", "['Jan']", "]"} {
+		if _, err := ToGoLayout(layout); err == nil {
+			t.Errorf("%v: expected error", layout)
+		}
+	}
+}
+
+func TestFormatGo(t *testing.T) {
+	mst := time.FixedZone("MST", -7*3600)
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 123456789, mst)
+	tests := []struct {
+		loc      *locale.Locale
+		goLayout string
+		out      string
+	}{
+		{locale.EnUS, time.RFC1123, "Mon, 02 Jan 2006 15:04:05 MST"},
+		{locale.EnUS, time.RFC3339, "2006-01-02T15:04:05-07:00"},
+		{locale.EnUS, time.StampMilli, "Jan  2 15:04:05.123"},
+		{locale.EnUS, time.Kitchen, "3:04PM"},
+		{locale.EnUS, "_2006", "_2006"},
+		{locale.EnUS, "15:04:05.999", "15:04:05!(BADFIELD)"},
+		{locale.FrFR, "Monday 2 January 2006", "lundi 2 janvier 2006"},
+		{locale.FrFR, "Mon 2 Jan", "lun. 2 janv."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.goLayout, func(t *testing.T) {
+			out := FormatGo(test.loc, test.goLayout, ts)
+			if out != test.out {
+				t.Errorf("\n have: %v \n want: %v", out, test.out)
+			}
+		})
+	}
+
+	// Without locale names, the result matches the standard library
+	for _, goLayout := range []string{time.ANSIC, time.RFC822Z, time.RFC3339, time.Kitchen, time.StampMicro} {
+		if have, want := FormatGo(locale.EnUS, goLayout, ts), ts.Format(goLayout); have != want {
+			t.Errorf("%v\n have: %v \n want: %v", goLayout, have, want)
+		}
+	}
+	utc := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	if have, want := FormatGo(locale.EnUS, time.RFC3339, utc), utc.Format(time.RFC3339); have != want {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
@@ -0,0 +1,563 @@
+package ptime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// isoParser is a strict parser for the formats found in ISO 8601. Unlike
+// the main parser, it works directly on the text instead of tokens since
+// the basic format does not have separators between fields.
+type isoParser struct {
+	loc      *locale.Locale
+	text     string
+	pos      int
+	parsed   Parsed
+	extended bool
+}
+
+func (p *parseContext) parseISO(text string) (Parsed, error) {
+	ip := &isoParser{loc: p.loc, text: text}
+	var err error
+	switch p.state {
+	case parsingDate:
+		err = ip.parseDate()
+	case parsingTime:
+		ip.accept("T")
+		err = ip.parseTime(true)
+	default:
+		if ip.accept("T") || (len(text) > 2 && text[2] == ':') {
+			err = ip.parseTime(true)
+		} else {
+			err = ip.parseDateTime()
+		}
+	}
+	if err != nil {
+		return ip.parsed, err
+	}
+	if ip.pos < len(text) {
+		return ip.parsed, ip.err(ErrUnexpectedText, "", "unexpected text: %v", text[ip.pos:])
+	}
+	return ip.parsed, nil
+}
+
+func (ip *isoParser) parseDateTime() error {
+	if err := ip.parseDate(); err != nil {
+		return err
+	}
+	if ip.accept("T") {
+		ip.parsed.DateTimeSep = "T"
+		return ip.parseTime(false)
+	}
+	return nil
+}
+
+func (ip *isoParser) parseDate() error {
+	// A signed year may have more than four digits in the extended format,
+	// such as "+10000-01-01". A negative year is an astronomical year where
+	// "-0001" is 2 BC.
+	sign := ""
+	signed := true
+	switch {
+	case ip.accept("-"):
+		sign = "-"
+	case ip.accept("+"):
+	default:
+		signed = false
+	}
+	n := 4
+	if d := ip.peekDigits(); signed && d > 4 && strings.HasPrefix(ip.text[ip.pos+d:], "-") {
+		n = d
+	}
+	year, ok := ip.digits(n)
+	if !ok {
+		return ip.err(ErrInvalidYear, "Year", "expecting four digit year")
+	}
+	ip.parsed.Year = sign + year
+
+	if ip.accept("-") {
+		ip.extended = true
+		ip.parsed.DateSep = "-"
+	}
+	if ip.accept("W") {
+		return ip.parseWeekDate()
+	}
+
+	digits := ip.peekDigits()
+	switch {
+	case ip.extended && digits == 2:
+		month, _ := ip.digits(2)
+		if err := ip.checkRange(month, 1, 12, ErrInvalidMonth, "Month"); err != nil {
+			return err
+		}
+		ip.parsed.Month = month
+		if !ip.accept("-") {
+			return nil
+		}
+		day, ok := ip.digits(2)
+		if !ok {
+			return ip.err(ErrInvalidDay, "Day", "expecting two digit day")
+		}
+		return ip.setDay(day)
+	case !ip.extended && digits == 4:
+		month, _ := ip.digits(2)
+		if err := ip.checkRange(month, 1, 12, ErrInvalidMonth, "Month"); err != nil {
+			return err
+		}
+		ip.parsed.Month = month
+		day, _ := ip.digits(2)
+		return ip.setDay(day)
+	case digits == 3:
+		day, _ := ip.digits(3)
+		if err := ip.checkRange(day, 1, 366, ErrInvalidDay, "Day"); err != nil {
+			return err
+		}
+		ip.parsed.Day = day
+		return nil
+	case digits == 0 && !ip.extended:
+		return nil
+	}
+	return ip.err(ErrInvalidDate, "", "invalid date")
+}
+
+func (ip *isoParser) parseWeekDate() error {
+	week, ok := ip.digits(2)
+	if !ok {
+		return ip.err(ErrInvalidDate, "Week", "expecting two digit week")
+	}
+	year, _ := strconv.Atoi(ip.parsed.Year)
+	if err := ip.checkRange(week, 1, isoWeeks(year), ErrInvalidDate, "Week"); err != nil {
+		return err
+	}
+	ip.parsed.Week = week
+	if ip.extended && !ip.accept("-") {
+		return nil
+	}
+	wd, ok := ip.digits(1)
+	if !ok {
+		if ip.extended {
+			return ip.err(ErrInvalidDate, "Weekday", "expecting weekday")
+		}
+		return nil
+	}
+	if err := ip.checkRange(wd, 1, 7, ErrInvalidDate, "Weekday"); err != nil {
+		return err
+	}
+	n, _ := strconv.Atoi(wd)
+	ip.parsed.Weekday = ip.loc.DayNamesAbbr[n%7]
+	return nil
+}
+
+func (ip *isoParser) setDay(day string) error {
+	if err := ip.checkRange(day, 1, 31, ErrInvalidDay, "Day"); err != nil {
+		return err
+	}
+	ip.parsed.Day = day
+	return nil
+}
+
+// parseTime parses the time of day. If standalone is false, the time
+// follows a date and must use the same format (basic or extended).
+func (ip *isoParser) parseTime(standalone bool) error {
+	hour, ok := ip.digits(2)
+	if !ok {
+		return ip.err(ErrInvalidHour, "Hour", "expecting two digit hour")
+	}
+	if err := ip.checkRange(hour, 0, 23, ErrInvalidHour, "Hour"); err != nil {
+		return err
+	}
+	ip.parsed.Hour = hour
+
+	extended := ip.peek() == ':'
+	if ip.peekDigits() >= 2 || extended {
+		if !standalone && extended != ip.extended {
+			return ip.err(ErrInvalidMinute, "Minute", "basic and extended formats cannot be mixed")
+		}
+		if extended {
+			ip.accept(":")
+			ip.parsed.TimeSep = ":"
+		}
+		minute, ok := ip.digits(2)
+		if !ok {
+			return ip.err(ErrInvalidMinute, "Minute", "expecting two digit minute")
+		}
+		if err := ip.checkRange(minute, 0, 59, ErrInvalidMinute, "Minute"); err != nil {
+			return err
+		}
+		ip.parsed.Minute = minute
+
+		if (extended && ip.accept(":")) || (!extended && ip.peekDigits() >= 2) {
+			second, ok := ip.digits(2)
+			if !ok {
+				return ip.err(ErrInvalidSecond, "Second", "expecting two digit second")
+			}
+			if err := ip.checkRange(second, 0, 59, ErrInvalidSecond, "Second"); err != nil {
+				return err
+			}
+			ip.parsed.Second = second
+		}
+	}
+
+	if ip.peek() == '.' || ip.peek() == ',' {
+		ip.pos++
+		n := ip.peekDigits()
+		if n == 0 {
+			return ip.err(ErrInvalidSecond, "FracSecond", "expecting fraction")
+		}
+		if ip.parsed.Second == "" {
+			return ip.err(ErrInvalidSecond, "FracSecond", "fractions are only supported for seconds")
+		}
+		ip.parsed.FracSecond, _ = ip.digits(n)
+	}
+	return ip.parseZone()
+}
+
+func (ip *isoParser) parseZone() error {
+	if ip.accept("Z") {
+		ip.parsed.Zone = "Z"
+		ip.parsed.Offset = "+0000"
+		return nil
+	}
+	sign := ip.peek()
+	if sign != '+' && sign != '-' {
+		return nil
+	}
+	ip.pos++
+	hours, ok := ip.digits(2)
+	if !ok {
+		return ip.err(ErrInvalidOffset, "Offset", "expecting two digit offset hours")
+	}
+	minutes := "00"
+	if ip.accept(":") || ip.peekDigits() > 0 {
+		if minutes, ok = ip.digits(2); !ok {
+			return ip.err(ErrInvalidOffset, "Offset", "expecting two digit offset minutes")
+		}
+	}
+	ip.parsed.Offset = string(sign) + hours + minutes
+	return nil
+}
+
+func (ip *isoParser) peek() byte {
+	if ip.pos >= len(ip.text) {
+		return 0
+	}
+	return ip.text[ip.pos]
+}
+
+func (ip *isoParser) peekDigits() int {
+	n := 0
+	for i := ip.pos; i < len(ip.text) && isDigit(ip.text[i]); i++ {
+		n++
+	}
+	return n
+}
+
+func (ip *isoParser) digits(n int) (string, bool) {
+	if ip.peekDigits() < n {
+		return "", false
+	}
+	v := ip.text[ip.pos : ip.pos+n]
+	ip.pos += n
+	return v, true
+}
+
+func (ip *isoParser) accept(s string) bool {
+	if strings.HasPrefix(ip.text[ip.pos:], s) {
+		ip.pos += len(s)
+		return true
+	}
+	return false
+}
+
+func (ip *isoParser) checkRange(v string, min int, max int, code ErrorCode, field string) error {
+	n, _ := strconv.Atoi(v)
+	if n < min || n > max {
+		return &ParseError{
+			Code:  code,
+			Field: field,
+			Token: Token{Number, v, ip.pos - len(v) + 1},
+			State: "ISO8601",
+			Msg:   fmt.Sprintf("invalid %v: %v", strings.ToLower(field), v),
+		}
+	}
+	return nil
+}
+
+func (ip *isoParser) err(code ErrorCode, field string, format string, a ...any) error {
+	tok := Token{End, "", ip.pos + 1}
+	if ip.pos < len(ip.text) {
+		tok = Token{Text, ip.text[ip.pos:], ip.pos + 1}
+	}
+	return &ParseError{
+		Code:  code,
+		Field: field,
+		Token: tok,
+		State: "ISO8601",
+		Msg:   fmt.Sprintf(format, a...),
+	}
+}
+
+// fracToNsec converts the digits after the decimal point to nanoseconds
+func fracToNsec(frac string) int {
+	frac = (frac + "000000000")[:9]
+	n, _ := strconv.Atoi(frac)
+	return n
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+// Duration is an amount of time as given in ISO 8601, for example
+// "P3Y6M4DT12H30M5S". Years, months, weeks and days are calendar units
+// and are not converted to a fixed number of hours.
+type Duration struct {
+	Years       int
+	Months      int
+	Weeks       int
+	Days        int
+	Hours       int
+	Minutes     int
+	Seconds     int
+	Nanoseconds int
+}
+
+func (d Duration) IsZero() bool {
+	return d == Duration{}
+}
+
+func (d Duration) AddTo(t time.Time) time.Time {
+	return d.add(t, 1)
+}
+
+func (d Duration) SubtractFrom(t time.Time) time.Time {
+	return d.add(t, -1)
+}
+
+func (d Duration) add(t time.Time, sign int) time.Time {
+	t = t.AddDate(sign*d.Years, sign*d.Months, sign*(d.Weeks*7+d.Days))
+	clock := time.Duration(d.Hours)*time.Hour +
+		time.Duration(d.Minutes)*time.Minute +
+		time.Duration(d.Seconds)*time.Second +
+		time.Duration(d.Nanoseconds)
+	return t.Add(time.Duration(sign) * clock)
+}
+
+func (d Duration) String() string {
+	var b strings.Builder
+	b.WriteString("P")
+	write := func(n int, unit string) {
+		if n != 0 {
+			fmt.Fprintf(&b, "%v%v", n, unit)
+		}
+	}
+	write(d.Years, "Y")
+	write(d.Months, "M")
+	write(d.Weeks, "W")
+	write(d.Days, "D")
+	if d.Hours != 0 || d.Minutes != 0 || d.Seconds != 0 || d.Nanoseconds != 0 {
+		b.WriteString("T")
+		write(d.Hours, "H")
+		write(d.Minutes, "M")
+		if d.Nanoseconds != 0 {
+			frac := strings.TrimRight(fmt.Sprintf("%09d", d.Nanoseconds), "0")
+			fmt.Fprintf(&b, "%v.%vS", d.Seconds, frac)
+		} else {
+			write(d.Seconds, "S")
+		}
+	}
+	if b.Len() == 1 {
+		b.WriteString("T0S")
+	}
+	return b.String()
+}
+
+// ParseDuration parses an ISO 8601 duration such as "P3Y6M4DT12H30M5S".
+// A decimal fraction is only allowed on the seconds.
+func ParseDuration(text string) (Duration, error) {
+	var d Duration
+	ip := &isoParser{text: text}
+	if !ip.accept("P") {
+		return d, ip.err(ErrInvalidDate, "Duration", "expecting 'P' at start of duration")
+	}
+	dateUnits := map[byte]*int{'Y': &d.Years, 'M': &d.Months, 'W': &d.Weeks, 'D': &d.Days}
+	timeUnits := map[byte]*int{'H': &d.Hours, 'M': &d.Minutes, 'S': &d.Seconds}
+	dateOrder := "YMWD"
+	timeOrder := "HMS"
+
+	units, order := dateUnits, dateOrder
+	inTime := false
+	count := 0
+	last := -1
+	for ip.pos < len(text) {
+		if !inTime && ip.accept("T") {
+			units, order = timeUnits, timeOrder
+			inTime = true
+			last = -1
+			if ip.pos == len(text) {
+				return d, ip.err(ErrInvalidDate, "Duration", "expecting time after 'T'")
+			}
+			continue
+		}
+		n := ip.peekDigits()
+		if n == 0 {
+			return d, ip.err(ErrInvalidDate, "Duration", "expecting number")
+		}
+		num, _ := ip.digits(n)
+		frac := ""
+		if ip.peek() == '.' || ip.peek() == ',' {
+			ip.pos++
+			if frac, _ = ip.digits(ip.peekDigits()); frac == "" {
+				return d, ip.err(ErrInvalidDate, "Duration", "expecting fraction")
+			}
+		}
+		unit := ip.peek()
+		i := strings.IndexByte(order, unit)
+		if unit == 0 || i <= last {
+			return d, ip.err(ErrInvalidDate, "Duration", "invalid duration unit")
+		}
+		if frac != "" && unit != 'S' {
+			return d, ip.err(ErrInvalidDate, "Duration", "fractions are only supported for seconds")
+		}
+		ip.pos++
+		last = i
+		count++
+		v, err := strconv.Atoi(num)
+		if err != nil {
+			return d, ip.err(ErrInvalidDate, "Duration", "invalid number: %v", num)
+		}
+		*units[unit] = v
+		if frac != "" {
+			d.Nanoseconds = fracToNsec(frac)
+		}
+	}
+	if count == 0 {
+		return d, ip.err(ErrInvalidDate, "Duration", "empty duration")
+	}
+	return d, nil
+}
+
+// Interval is a time interval as given in ISO 8601. It is either a start
+// and an end, a start and a duration, or a duration and an end.
+type Interval struct {
+	Start    Parsed
+	End      Parsed
+	Duration Duration
+}
+
+// ParseInterval parses an ISO 8601 interval such as
+// "2006-01-02/2006-02-01", "2006-01-02T15:00Z/PT1H", or
+// "P1D/2006-01-02". The parts can be separated by "/" or "--".
+func (p *Parser) ParseInterval(text string) (Interval, error) {
+	var iv Interval
+	sep := "/"
+	i := strings.Index(text, sep)
+	if i < 0 {
+		sep = "--"
+		i = strings.Index(text, sep)
+	}
+	if i < 0 {
+		return iv, &ParseError{
+			Code:  ErrInvalidDate,
+			Token: Token{End, "", len(text) + 1},
+			State: "ISO8601",
+			Msg:   "expecting '/' in interval",
+		}
+	}
+	first, second := text[:i], text[i+len(sep):]
+
+	iso := &Parser{loc: p.loc, Trace: p.Trace, ISO8601: true}
+	var err error
+	switch {
+	case strings.HasPrefix(first, "P") && strings.HasPrefix(second, "P"):
+		return iv, &ParseError{
+			Code:  ErrInvalidDate,
+			Token: Token{Text, second, i + len(sep) + 1},
+			State: "ISO8601",
+			Msg:   "interval cannot have two durations",
+		}
+	case strings.HasPrefix(first, "P"):
+		if iv.Duration, err = ParseDuration(first); err != nil {
+			return iv, err
+		}
+		iv.End, err = iso.Parse(second)
+	case strings.HasPrefix(second, "P"):
+		if iv.Start, err = iso.Parse(first); err != nil {
+			return iv, err
+		}
+		iv.Duration, err = ParseDuration(second)
+	default:
+		if iv.Start, err = iso.Parse(first); err != nil {
+			return iv, err
+		}
+		if iv.End, err = iso.Parse(second); err != nil {
+			return iv, err
+		}
+		iv.End = inheritDate(iv.End, iv.Start)
+	}
+	return iv, err
+}
+
+// inheritDate fills in an end that only has a time, as in
+// "2007-12-14T13:30/15:30", with the date of the start. The zone of the
+// start is also used if the end does not have one. An end with a partial
+// date, such as "03-14", is not accepted by the strict parser.
+func inheritDate(end Parsed, start Parsed) Parsed {
+	if hasDate(end) {
+		return end
+	}
+	copyDate(&end, start)
+	if end.Zone == "" && end.Offset == "" && end.Location == "" {
+		end.Zone, end.Offset, end.Location = start.Zone, start.Offset, start.Location
+	}
+	return end
+}
+
+func IntervalTime(l *locale.Locale, iv Interval, now time.Time) (time.Time, time.Time, error) {
+	return NewParser(l).IntervalTime(iv, now)
+}
+
+// IntervalTime returns the start and end times for the interval. A
+// duration is added to the start or subtracted from the end.
+func (p *Parser) IntervalTime(iv Interval, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	var err error
+	if iv.Start != (Parsed{}) {
+		if start, err = p.Time(iv.Start, now); err != nil {
+			return start, end, err
+		}
+	}
+	if iv.End != (Parsed{}) {
+		if end, err = p.Time(iv.End, now); err != nil {
+			return start, end, err
+		}
+	}
+	switch {
+	case iv.Start == (Parsed{}):
+		start = iv.Duration.SubtractFrom(end)
+	case iv.End == (Parsed{}):
+		end = iv.Duration.AddTo(start)
+	}
+	return start, end, nil
+}
+
+// isoWeeks returns the number of weeks, 52 or 53, in the ISO
+// week-numbering year. December 28th is always in the last week.
+func isoWeeks(year int) int {
+	_, week := time.Date(year, 12, 28, 0, 0, 0, 0, time.UTC).ISOWeek()
+	return week
+}
+
+// isoWeekDate returns the date for the weekday (1 is Monday) in the given
+// ISO week of the ISO week-numbering year.
+func isoWeekDate(year int, week int, weekday int, loc *time.Location) time.Time {
+	// January 4th is always in week 1
+	jan4 := time.Date(year, 1, 4, 0, 0, 0, 0, loc)
+	offset := (int(jan4.Weekday()) + 6) % 7
+	monday := jan4.AddDate(0, 0, -offset)
+	return monday.AddDate(0, 0, (week-1)*7+weekday-1)
+}
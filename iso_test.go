@@ -0,0 +1,277 @@
+package ptime
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestParserISO8601(t *testing.T) {
+	tests := []struct {
+		fn     string
+		text   string
+		parsed Parsed
+	}{
+		{"parse", "2006-01-02", Parsed{Year: "2006", Month: "01", Day: "02", DateSep: "-"}},
+		{"parse", "20060102", Parsed{Year: "2006", Month: "01", Day: "02"}},
+		{"parse", "2006-01", Parsed{Year: "2006", Month: "01", DateSep: "-"}},
+		{"parse", "2006", Parsed{Year: "2006"}},
+		{"parse", "2006-002", Parsed{Year: "2006", Day: "002", DateSep: "-"}},
+		{"parse", "2006002", Parsed{Year: "2006", Day: "002"}},
+		{"parse", "2006-W01", Parsed{Year: "2006", Week: "01", DateSep: "-"}},
+		{"parse", "2006-W01-1", Parsed{Year: "2006", Week: "01", Weekday: "Mon", DateSep: "-"}},
+		{"parse", "2006W017", Parsed{Year: "2006", Week: "01", Weekday: "Sun"}},
+		{"parse", "2009-W53-7", Parsed{Year: "2009", Week: "53", Weekday: "Sun", DateSep: "-"}},
+		{"parse", "-0043-03-15", Parsed{Year: "-0043", Month: "03", Day: "15", DateSep: "-"}},
+		{"parse", "+10000-01-02", Parsed{Year: "10000", Month: "01", Day: "02", DateSep: "-"}},
+		{"parse", "-00430315", Parsed{Year: "-0043", Month: "03", Day: "15"}},
+		{"parse", "2006-01-02T15:04:05Z", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Zone: "Z", Offset: "+0000", DateSep: "-", TimeSep: ":", DateTimeSep: "T",
+		}},
+		{"parse", "20060102T150405,123-0700", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			FracSecond: "123", Offset: "-0700", DateTimeSep: "T",
+		}},
+		{"parse", "2006-01-02T15:04+05:30", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04",
+			Offset: "+0530", DateSep: "-", TimeSep: ":", DateTimeSep: "T",
+		}},
+		{"parse", "15:04:05.5", Parsed{Hour: "15", Minute: "04", Second: "05", FracSecond: "5", TimeSep: ":"}},
+		{"parse", "T1504", Parsed{Hour: "15", Minute: "04"}},
+		{"date", "2006-W52-7", Parsed{Year: "2006", Week: "52", Weekday: "Sun", DateSep: "-"}},
+		{"time", "150405Z", Parsed{Hour: "15", Minute: "04", Second: "05", Zone: "Z", Offset: "+0000"}},
+		{"time", "T15", Parsed{Hour: "15"}},
+	}
+
+	p := NewParser(locale.EnUS)
+	p.ISO8601 = true
+	for _, test := range tests {
+		t.Run(test.fn+":"+test.text, func(t *testing.T) {
+			var parsed Parsed
+			var err error
+			switch test.fn {
+			case "parse":
+				parsed, err = p.Parse(test.text)
+			case "date":
+				parsed, err = p.ParseDate(test.text)
+			case "time":
+				parsed, err = p.ParseTime(test.text)
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, test.parsed) {
+				t.Errorf("\n have: %v \n want: %v", parsed, test.parsed)
+			}
+		})
+	}
+}
+
+func TestParserISO8601Error(t *testing.T) {
+	tests := []struct {
+		text string
+		code ErrorCode
+		pos  int
+	}{
+		{"2006-13-01", ErrInvalidMonth, 6},
+		{"２００６-13-01", ErrInvalidMonth, 14},
+		{"2006-01-32", ErrInvalidDay, 9},
+		{"2006-W54", ErrInvalidDate, 7},
+		{"2006-W53", ErrInvalidDate, 7},
+		{"2006-0102", ErrInvalidDate, 6},
+		{"200601", ErrInvalidDate, 5},
+		{"2006-01-02T150405", ErrInvalidMinute, 14},
+		{"20060102T15:04", ErrInvalidMinute, 12},
+		{"2006-01-02T25:00", ErrInvalidHour, 12},
+		{"2006-01-02T15:04.5", ErrInvalidSecond, 18},
+		{"Jan 2 2006", ErrInvalidYear, 1},
+		{"-043-01-01", ErrInvalidYear, 2},
+		{"2006-01-02 15:04", ErrUnexpectedText, 11},
+	}
+
+	p := NewParser(locale.EnUS)
+	p.ISO8601 = true
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			_, err := p.Parse(test.text)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected parse error, have: %v", err)
+			}
+			if perr.Code != test.code || perr.Token.Pos != test.pos {
+				t.Errorf("\n have: %v at %v (%v) \n want: %v at %v", perr.Code, perr.Token.Pos, perr, test.code, test.pos)
+			}
+		})
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		text string
+		dur  Duration
+		str  string
+	}{
+		{"P3Y6M4DT12H30M5S", Duration{Years: 3, Months: 6, Days: 4, Hours: 12, Minutes: 30, Seconds: 5}, ""},
+		{"P2W", Duration{Weeks: 2}, ""},
+		{"PT1.5S", Duration{Seconds: 1, Nanoseconds: 500000000}, ""},
+		{"PT0,25S", Duration{Nanoseconds: 250000000}, "PT0.25S"},
+		{"P1M", Duration{Months: 1}, ""},
+		{"PT1M", Duration{Minutes: 1}, ""},
+		{"PT0S", Duration{}, ""},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			dur, err := ParseDuration(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if dur != test.dur {
+				t.Errorf("\n have: %+v \n want: %+v", dur, test.dur)
+			}
+			str := test.str
+			if str == "" {
+				str = test.text
+			}
+			if dur.String() != str {
+				t.Errorf("\n have: %v \n want: %v", dur.String(), str)
+			}
+		})
+	}
+}
+
+func TestParseDurationError(t *testing.T) {
+	tests := []struct {
+		text string
+		err  string
+	}{
+		{"3D", "expecting 'P'"},
+		{"P", "empty duration"},
+		{"PT", "expecting time after 'T'"},
+		{"P1D2Y", "invalid duration unit"},
+		{"P1H", "invalid duration unit"},
+		{"P1.5D", "only supported for seconds"},
+		{"PD", "expecting number"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			_, err := ParseDuration(test.text)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if !strings.Contains(err.Error(), test.err) {
+				t.Errorf("\n have: %v \n want: %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestParseInterval(t *testing.T) {
+	now := time.Date(2006, 01, 02, 15, 04, 05, 0, time.UTC)
+	tests := []struct {
+		text  string
+		start time.Time
+		end   time.Time
+	}{
+		{"2006-01-02/2006-02-01",
+			time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2006, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{"2006-01-02T15:00Z/PT1H30M",
+			time.Date(2006, 1, 2, 15, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 2, 16, 30, 0, 0, time.UTC)},
+		{"P1D/2006-01-02T00:00Z",
+			time.Date(2006, 1, 1, 0, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"2006-W01-1--2006-W01-7",
+			time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 8, 0, 0, 0, 0, time.UTC)},
+		{"2007-12-14T13:30/15:30",
+			time.Date(2007, 12, 14, 13, 30, 0, 0, time.UTC),
+			time.Date(2007, 12, 14, 15, 30, 0, 0, time.UTC)},
+		{"2007-12-14T13:30+01:00/15:30",
+			time.Date(2007, 12, 14, 12, 30, 0, 0, time.UTC),
+			time.Date(2007, 12, 14, 14, 30, 0, 0, time.UTC)},
+	}
+
+	p := For(locale.EnUS)
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			iv, err := p.ParseInterval(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			start, end, err := p.IntervalTime(iv, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !start.Equal(test.start) || !end.Equal(test.end) {
+				t.Errorf("\n have: %v - %v \n want: %v - %v", start, end, test.start, test.end)
+			}
+		})
+	}
+}
+
+func TestParseIntervalError(t *testing.T) {
+	p := For(locale.EnUS)
+	for _, text := range []string{"2006-01-02", "P1D/P2D", "2006-01-02/PX", "2008-02-15/03-14"} {
+		if _, err := p.ParseInterval(text); err == nil {
+			t.Errorf("%v: expected error", text)
+		}
+	}
+}
+
+func TestTimeWeek(t *testing.T) {
+	now := time.Date(2006, 01, 02, 15, 04, 05, 0, time.UTC)
+	tests := []struct {
+		parsed Parsed
+		time   time.Time
+	}{
+		{Parsed{Year: "2009", Week: "01", Weekday: "Mon"}, time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC)},
+		{Parsed{Year: "2009", Week: "53", Weekday: "Sun"}, time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{Parsed{Year: "2006", Week: "10"}, time.Date(2006, 3, 6, 0, 0, 0, 0, time.UTC)},
+		{Parsed{Week: "02", Weekday: "Wed"}, time.Date(2006, 1, 11, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.parsed.String(), func(t *testing.T) {
+			have, err := Time(locale.EnUS, test.parsed, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !have.Equal(test.time) {
+				t.Errorf("\n have: %v \n want: %v", have, test.time)
+			}
+		})
+	}
+
+	if _, err := Time(locale.EnUS, Parsed{Year: "2006", Week: "53"}, now); err == nil {
+		t.Errorf("expected error for week 53 of 2006")
+	}
+}
+
+func TestFormatWeek(t *testing.T) {
+	tests := []struct {
+		time   time.Time
+		layout string
+		out    string
+	}{
+		{time.Date(2008, 12, 29, 0, 0, 0, 0, time.UTC), "[year/week]-W[week/02]-[weekday/iso]", "2009-W01-1"},
+		{time.Date(2010, 1, 3, 0, 0, 0, 0, time.UTC), "[year/week]-W[week]-[weekday/iso]", "2009-W53-7"},
+		{time.Date(2006, 3, 6, 0, 0, 0, 0, time.UTC), "[week]", "10"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout, func(t *testing.T) {
+			out := Format(locale.EnUS, test.layout, test.time)
+			if out != test.out {
+				t.Errorf("\n have: %v \n want: %v", out, test.out)
+			}
+		})
+	}
+}
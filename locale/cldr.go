@@ -0,0 +1,412 @@
+package locale
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode"
+)
+
+type cldrItem struct {
+	Type  string `xml:"type,attr"`
+	Alt   string `xml:"alt,attr"`
+	Value string `xml:",chardata"`
+}
+
+type cldrWidth struct {
+	Type  string     `xml:"type,attr"`
+	Items []cldrItem `xml:",any"`
+}
+
+type cldrContext struct {
+	Type   string      `xml:"type,attr"`
+	Widths []cldrWidth `xml:",any"`
+}
+
+type cldrFormatLength struct {
+	Type        string     `xml:"type,attr"`
+	DatePattern []cldrItem `xml:"dateFormat>pattern"`
+	TimePattern []cldrItem `xml:"timeFormat>pattern"`
+}
+
+type cldrSymbols struct {
+	NumberSystem  string `xml:"numberSystem,attr"`
+	Decimal       string `xml:"decimal"`
+	TimeSeparator string `xml:"timeSeparator"`
+}
+
+type cldrCalendar struct {
+	Type        string             `xml:"type,attr"`
+	Months      []cldrContext      `xml:"months>monthContext"`
+	Days        []cldrContext      `xml:"days>dayContext"`
+	DayPeriods  []cldrContext      `xml:"dayPeriods>dayPeriodContext"`
+	EraAbbr     []cldrItem         `xml:"eras>eraAbbr>era"`
+	DateFormats []cldrFormatLength `xml:"dateFormats>dateFormatLength"`
+	TimeFormats []cldrFormatLength `xml:"timeFormats>timeFormatLength"`
+}
+
+type cldrLDML struct {
+	Calendars []cldrCalendar `xml:"dates>calendars>calendar"`
+	Numbers   struct {
+		DefaultNumberingSystem string        `xml:"defaultNumberingSystem"`
+		Symbols                []cldrSymbols `xml:"symbols"`
+	} `xml:"numbers"`
+}
+
+// cldrChain is the list of documents for a locale starting with the most
+// specific (e.g. fr_CA) and ending with root. Values are taken from the
+// first document that defines them.
+type cldrChain []*cldrLDML
+
+var cldrMonthKeys = []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12"}
+var cldrDayKeys = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+var cldrPeriodKeys = map[int]string{
+	AM:       "am",
+	PM:       "pm",
+	Noon:     "noon",
+	Midnight: "midnight",
+}
+
+var cldrEraKeys = map[string]int{
+	"0": BC,
+	"1": AD,
+}
+
+// FromCLDR creates a locale definition from the CLDR XML data found in dir.
+// The directory is expected to contain the "main" directory from the CLDR
+// distribution. The name can be given as either "fr-CA" or "fr_CA" and
+// values missing from that locale are inherited from "fr" and then "root".
+func FromCLDR(dir string, name string) (Def, error) {
+	chain, err := loadCLDRChain(dir, name)
+	if err != nil {
+		return Def{}, err
+	}
+
+	var def Def
+	if def.MonthNamesWide, err = chain.names("months", "wide", cldrMonthKeys); err != nil {
+		return Def{}, err
+	}
+	if def.MonthNamesAbbr, err = chain.names("months", "abbreviated", cldrMonthKeys); err != nil {
+		return Def{}, err
+	}
+	def.MonthNamesNarrow, _ = chain.names("months", "narrow", cldrMonthKeys)
+	if def.DayNamesWide, err = chain.names("days", "wide", cldrDayKeys); err != nil {
+		return Def{}, err
+	}
+	if def.DayNamesAbbr, err = chain.names("days", "abbreviated", cldrDayKeys); err != nil {
+		return Def{}, err
+	}
+	def.DayNamesShort, _ = chain.names("days", "short", cldrDayKeys)
+	def.DayNamesNarrow, _ = chain.names("days", "narrow", cldrDayKeys)
+	def.PeriodNamesAbbr = chain.periods("abbreviated")
+	def.PeriodNamesNarrow = chain.periods("narrow")
+	def.EraNames = chain.eras()
+
+	datePattern := chain.pattern(func(c *cldrCalendar) []cldrFormatLength { return c.DateFormats }, "short")
+	timePattern := chain.pattern(func(c *cldrCalendar) []cldrFormatLength { return c.TimeFormats }, "short")
+	if datePattern == "" {
+		return Def{}, fmt.Errorf("no short date pattern for locale: %v", name)
+	}
+
+	fields := patternFields(datePattern)
+	def.MonthDayOrder = strings.Index(fields, "M") < strings.Index(fields, "d")
+	def.DateSep = patternSeps(datePattern, "yMLd")
+	if !inList("-", def.DateSep) {
+		def.DateSep = append(def.DateSep, "-")
+	}
+
+	if sep := chain.symbol(func(s cldrSymbols) string { return s.TimeSeparator }); sep != "" {
+		def.TimeSep = []string{sep}
+	} else {
+		def.TimeSep = patternSeps(timePattern, "Hhm")
+	}
+	if sep := patternHourSep(timePattern); sep != "" {
+		def.HourSep = []string{sep}
+	}
+	def.DecimalSep = chain.symbol(func(s cldrSymbols) string { return s.Decimal })
+	if def.DecimalSep == "" {
+		def.DecimalSep = "."
+	}
+	// Numbering systems that are not a run of ten digits, such as the
+	// Chinese "hanidec", are left as the default
+	if ns := chain.numberingSystem(); ns != "latn" {
+		if _, ok := numberingSystems[ns]; ok {
+			def.NumberingSystem = ns
+		}
+	}
+
+	def.ZoneNamesShort = map[string]string{"UTC": "+0000"}
+	def.DateTimeSep = []string{"T"}
+	def.UTCFlags = []string{"Z"}
+	def.RangeSep = []string{"-", "–"}
+	return def, nil
+}
+
+func loadCLDRChain(dir string, name string) (cldrChain, error) {
+	name = strings.ReplaceAll(name, "-", "_")
+	var names []string
+	for n := name; n != ""; {
+		names = append(names, n)
+		i := strings.LastIndex(n, "_")
+		if i < 0 {
+			break
+		}
+		n = n[:i]
+	}
+	if name != "root" {
+		names = append(names, "root")
+	}
+
+	var chain cldrChain
+	for _, n := range names {
+		data, err := os.ReadFile(filepath.Join(dir, "main", n+".xml"))
+		if errors.Is(err, fs.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		var doc cldrLDML
+		if err := xml.Unmarshal(data, &doc); err != nil {
+			return nil, fmt.Errorf("%v.xml: %v", n, err)
+		}
+		chain = append(chain, &doc)
+	}
+	// Only root was found
+	if len(chain) <= 1 && name != "root" {
+		return nil, fmt.Errorf("locale not found in CLDR data: %v", name)
+	}
+	return chain, nil
+}
+
+func (c cldrChain) gregorian(doc *cldrLDML) *cldrCalendar {
+	for i := range doc.Calendars {
+		if doc.Calendars[i].Type == "gregorian" {
+			return &doc.Calendars[i]
+		}
+	}
+	return nil
+}
+
+func (c cldrChain) contexts(cal *cldrCalendar, kind string) []cldrContext {
+	switch kind {
+	case "months":
+		return cal.Months
+	case "days":
+		return cal.Days
+	case "dayPeriods":
+		return cal.DayPeriods
+	}
+	return nil
+}
+
+// lookup finds the value for the item with the given key in the format
+// context of the calendar. Alternate values are only returned when asked for.
+func (c cldrChain) lookup(kind string, width string, key string, alt string) string {
+	for _, doc := range c {
+		cal := c.gregorian(doc)
+		if cal == nil {
+			continue
+		}
+		for _, ctx := range c.contexts(cal, kind) {
+			if ctx.Type != "format" {
+				continue
+			}
+			for _, w := range ctx.Widths {
+				if w.Type != width {
+					continue
+				}
+				for _, item := range w.Items {
+					if item.Type == key && item.Alt == alt {
+						return strings.TrimSpace(item.Value)
+					}
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (c cldrChain) names(kind string, width string, keys []string) ([]string, error) {
+	names := make([]string, len(keys))
+	for i, key := range keys {
+		names[i] = c.lookup(kind, width, key, "")
+		if names[i] == "" {
+			return nil, fmt.Errorf("missing %v name (%v): %v", kind, width, key)
+		}
+	}
+	return names, nil
+}
+
+func (c cldrChain) periods(width string) String2D {
+	periods := make(String2D, len(cldrPeriodKeys))
+	found := false
+	for i, key := range cldrPeriodKeys {
+		if name := c.lookup("dayPeriods", width, key, ""); name != "" {
+			periods[i] = append(periods[i], name)
+		}
+		if name := c.lookup("dayPeriods", width, key, "variant"); name != "" {
+			periods[i] = append(periods[i], name)
+		}
+		found = found || len(periods[i]) > 0
+	}
+	if !found {
+		return nil
+	}
+	return periods
+}
+
+// eras returns the abbreviated era names from the first document that has
+// them. The "variant" names, such as "BCE", follow the main name.
+func (c cldrChain) eras() String2D {
+	for _, doc := range c {
+		cal := c.gregorian(doc)
+		if cal == nil || len(cal.EraAbbr) == 0 {
+			continue
+		}
+		eras := make(String2D, 2)
+		for _, alt := range []string{"", "variant"} {
+			for _, item := range cal.EraAbbr {
+				n, ok := cldrEraKeys[item.Type]
+				if ok && item.Alt == alt && strings.TrimSpace(item.Value) != "" {
+					eras[n] = append(eras[n], strings.TrimSpace(item.Value))
+				}
+			}
+		}
+		return eras
+	}
+	return nil
+}
+
+func (c cldrChain) pattern(lengths func(*cldrCalendar) []cldrFormatLength, length string) string {
+	for _, doc := range c {
+		cal := c.gregorian(doc)
+		if cal == nil {
+			continue
+		}
+		for _, fl := range lengths(cal) {
+			if fl.Type != length {
+				continue
+			}
+			for _, p := range append(fl.DatePattern, fl.TimePattern...) {
+				if p.Alt == "" {
+					return p.Value
+				}
+			}
+		}
+	}
+	return ""
+}
+
+func (c cldrChain) numberingSystem() string {
+	for _, doc := range c {
+		if v := doc.Numbers.DefaultNumberingSystem; v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+func (c cldrChain) symbol(fn func(cldrSymbols) string) string {
+	for _, doc := range c {
+		for _, s := range doc.Numbers.Symbols {
+			if s.NumberSystem != "" && s.NumberSystem != "latn" {
+				continue
+			}
+			if v := fn(s); v != "" {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// patternFields returns the pattern letters found in a CLDR pattern with
+// repeated letters and quoted literals removed. For example, "dd/MM/y"
+// returns "dMy".
+func patternFields(pattern string) string {
+	var fields strings.Builder
+	var last rune
+	quoted := false
+	for _, ch := range pattern {
+		if ch == '\'' {
+			quoted = !quoted
+			continue
+		}
+		if quoted || !unicode.IsLetter(ch) {
+			last = 0
+			continue
+		}
+		if ch != last {
+			fields.WriteRune(ch)
+		}
+		last = ch
+	}
+	return fields.String()
+}
+
+// patternSeps returns the literal separators found between the pattern
+// letters in fields.
+func patternSeps(pattern string, fields string) []string {
+	var seps []string
+	var sep strings.Builder
+	inField := false
+	quoted := false
+	for _, ch := range pattern {
+		if ch == '\'' {
+			quoted = !quoted
+			continue
+		}
+		if !quoted && strings.ContainsRune(fields, ch) {
+			s := strings.TrimSpace(sep.String())
+			if inField && s != "" && !inList(s, seps) {
+				seps = append(seps, s)
+			}
+			sep.Reset()
+			inField = true
+			continue
+		}
+		if !quoted && unicode.IsLetter(ch) {
+			inField = false
+			sep.Reset()
+			continue
+		}
+		sep.WriteRune(ch)
+	}
+	return seps
+}
+
+// patternHourSep returns the quoted literal, if any, found between the hour
+// and the minute in a time pattern. For example, "HH 'h' mm" returns "h".
+func patternHourSep(pattern string) string {
+	i := strings.IndexAny(pattern, "Hh")
+	if i < 0 {
+		return ""
+	}
+	rest := pattern[i:]
+	j := strings.IndexRune(rest, 'm')
+	if j < 0 {
+		return ""
+	}
+	between := rest[:j]
+	start := strings.IndexRune(between, '\'')
+	end := strings.LastIndex(between, "'")
+	if start < 0 || start == end {
+		return ""
+	}
+	return strings.TrimSpace(between[start+1 : end])
+}
+
+func inList(v string, list []string) bool {
+	for _, item := range list {
+		if item == v {
+			return true
+		}
+	}
+	return false
+}
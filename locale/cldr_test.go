@@ -0,0 +1,103 @@
+package locale
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFromCLDR(t *testing.T) {
+	tests := []struct {
+		name string
+		want Def
+	}{
+		{"en-US", Def{
+			MonthDayOrder:    true,
+			MonthNamesWide:   EnMonthNamesWide,
+			MonthNamesAbbr:   EnMonthNamesAbbr,
+			MonthNamesNarrow: []string{"J", "F", "M", "A", "M", "J", "J", "A", "S", "O", "N", "D"},
+			DayNamesWide:     EnDayNamesWide,
+			DayNamesAbbr:     []string{"Sun", "Mon", "Tue", "Wed", "Thu", "Fri", "Sat"},
+			DayNamesShort:    []string{"Su", "Mo", "Tu", "We", "Th", "Fr", "Sa"},
+			DayNamesNarrow:   []string{"S", "M", "T", "W", "T", "F", "S"},
+			PeriodNamesAbbr:  EnPeriodNamesAbbr,
+			EraNames: String2D{
+				BC: []string{"BC", "BCE"},
+				AD: []string{"AD", "CE"},
+			},
+			PeriodNamesNarrow: String2D{
+				AM:       []string{"a"},
+				PM:       []string{"p"},
+				Noon:     []string{"n"},
+				Midnight: []string{"mi"},
+			},
+			ZoneNamesShort: map[string]string{"UTC": "+0000"},
+			DateSep:        []string{"/", "-"},
+			TimeSep:        []string{":"},
+			DecimalSep:     ".",
+			DateTimeSep:    []string{"T"},
+			UTCFlags:       []string{"Z"},
+			RangeSep:       []string{"-", "–"},
+		}},
+		{"fr_FR", Def{
+			MonthNamesWide: FrMonthNamesWide,
+			MonthNamesAbbr: FrMonthNamesAbbr,
+			DayNamesWide:   FrDayNamesWide,
+			DayNamesAbbr:   FrDayNamesAbbr,
+			DayNamesShort:  []string{"di", "lu", "ma", "me", "je", "ve", "sa"},
+			PeriodNamesAbbr: String2D{
+				AM:       []string{"AM"},
+				PM:       []string{"PM"},
+				Noon:     []string{"midi"},
+				Midnight: []string{"minuit"},
+			},
+			EraNames: String2D{
+				BC: []string{"av. J.-C.", "AEC"},
+				AD: []string{"ap. J.-C.", "EC"},
+			},
+			ZoneNamesShort: map[string]string{"UTC": "+0000"},
+			DateSep:        []string{"/", "-"},
+			TimeSep:        []string{":"},
+			DecimalSep:     ",",
+			DateTimeSep:    []string{"T"},
+			UTCFlags:       []string{"Z"},
+			RangeSep:       []string{"-", "–"},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			def, err := FromCLDR("testdata/cldr", test.name)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(def, test.want) {
+				t.Errorf("\n have: %#v \n want: %#v", def, test.want)
+			}
+			if _, err := New(def); err != nil {
+				t.Errorf("unable to create locale: %v", err)
+			}
+		})
+	}
+}
+
+func TestFromCLDRFrCA(t *testing.T) {
+	def, err := FromCLDR("testdata/cldr", "fr-CA")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(def.DateSep, []string{"-"}) {
+		t.Errorf("date sep: have %v want [-]", def.DateSep)
+	}
+	if !reflect.DeepEqual(def.HourSep, []string{"h"}) {
+		t.Errorf("hour sep: have %v want [h]", def.HourSep)
+	}
+	if !reflect.DeepEqual(def.MonthNamesWide, FrMonthNamesWide) {
+		t.Errorf("month names not inherited: %v", def.MonthNamesWide)
+	}
+}
+
+func TestFromCLDRMissing(t *testing.T) {
+	if _, err := FromCLDR("testdata/cldr", "xx"); err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -30,6 +30,21 @@ var EnMonthNamesAbbr = []string{
 	"Dec",
 }
 
+var EnMonthNamesNarrow = []string{
+	"J",
+	"F",
+	"M",
+	"A",
+	"M",
+	"J",
+	"J",
+	"A",
+	"S",
+	"O",
+	"N",
+	"D",
+}
+
 var EnDayNamesWide = []string{
 	"Sunday",
 	"Monday",
@@ -50,6 +65,26 @@ var EnDayNamesAbbr = []string{
 	"Sat",
 }
 
+var EnDayNamesShort = []string{
+	"Su",
+	"Mo",
+	"Tu",
+	"We",
+	"Th",
+	"Fr",
+	"Sa",
+}
+
+var EnDayNamesNarrow = []string{
+	"S",
+	"M",
+	"T",
+	"W",
+	"T",
+	"F",
+	"S",
+}
+
 var EnPeriodNamesAbbr = String2D{
 	AM:       []string{"AM", "am"},
 	PM:       []string{"PM", "pm"},
@@ -64,6 +99,38 @@ var EnPeriodNamesNarrow = String2D{
 	Noon:     []string{"n"},
 }
 
+var EnEraNames = String2D{
+	BC: []string{"BC", "BCE", "B.C.", "B.C.E."},
+	AD: []string{"AD", "CE", "A.D.", "C.E."},
+}
+
+var EnRelativeDayNames = map[string]int{
+	"yesterday": -1,
+	"today":     0,
+	"tomorrow":  1,
+}
+
+var EnUnitNames = String2D{
+	Seconds: []string{"second", "seconds", "sec", "secs"},
+	Minutes: []string{"minute", "minutes", "min", "mins"},
+	Hours:   []string{"hour", "hours", "hr", "hrs"},
+	Days:    []string{"day", "days"},
+	Weeks:   []string{"week", "weeks", "wk", "wks"},
+	Months:  []string{"month", "months"},
+	Years:   []string{"year", "years", "yr", "yrs"},
+}
+
+var EnOrdinalSuffixes = map[int]string{
+	0:  "th",
+	1:  "st",
+	2:  "nd",
+	3:  "rd",
+	21: "st",
+	22: "nd",
+	23: "rd",
+	31: "st",
+}
+
 var EnUSZonesShort = map[string]string{
 	"EST": "-0500",
 	"CST": "-0600",
@@ -76,18 +143,43 @@ var EnUSZonesShort = map[string]string{
 	"UTC": "+0000",
 }
 
+var EnUSZoneLocations = map[string]string{
+	"EST": "America/New_York",
+	"EDT": "America/New_York",
+	"CST": "America/Chicago",
+	"CDT": "America/Chicago",
+	"MST": "America/Denver",
+	"MDT": "America/Denver",
+	"PST": "America/Los_Angeles",
+	"PDT": "America/Los_Angeles",
+}
+
 var EnUS = MustNew(Def{
 	MonthDayOrder:     true,
 	MonthNamesWide:    EnMonthNamesWide,
 	MonthNamesAbbr:    EnMonthNamesAbbr,
+	MonthNamesNarrow:  EnMonthNamesNarrow,
 	DayNamesWide:      EnDayNamesWide,
 	DayNamesAbbr:      EnDayNamesAbbr,
+	DayNamesShort:     EnDayNamesShort,
+	DayNamesNarrow:    EnDayNamesNarrow,
 	PeriodNamesAbbr:   EnPeriodNamesAbbr,
 	PeriodNamesNarrow: EnPeriodNamesNarrow,
 	ZoneNamesShort:    EnUSZonesShort,
+	ZoneLocations:     EnUSZoneLocations,
 	DateSep:           []string{"-", "/"},
 	TimeSep:           []string{":"},
 	DecimalSep:        ".",
 	DateTimeSep:       []string{"T"},
 	UTCFlags:          []string{"Z"},
+	RelativeDayNames:  EnRelativeDayNames,
+	NextNames:         []string{"next"},
+	LastNames:         []string{"last"},
+	FutureNames:       []string{"in"},
+	PastNames:         []string{"ago"},
+	UnitNames:         EnUnitNames,
+	RangeSep:          []string{"-", "–", "to", "until", "through", "thru"},
+	RangeStartNames:   []string{"from"},
+	OrdinalSuffixes:   EnOrdinalSuffixes,
+	EraNames:          EnEraNames,
 })
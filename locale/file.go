@@ -0,0 +1,65 @@
+package locale
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FromJSON creates a locale from a definition in JSON. Keys that are not
+// the name of a field in Def are an error.
+func FromJSON(data []byte) (*Locale, error) {
+	var def Def
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&def); err != nil {
+		return nil, err
+	}
+	return New(def)
+}
+
+// LoadFile creates a locale from the JSON definition in the file at path.
+// The locale is not registered.
+func LoadFile(path string) (*Locale, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	l, err := FromJSON(data)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %w", path, err)
+	}
+	return l, nil
+}
+
+// LoadDir loads each file in dir that ends with ".json" and registers it
+// using the name of the file without the extension. For example, the
+// locale in "de-DE.json" is registered as "de-DE". Returns the names of the
+// locales that were registered. No locales are registered if any of the
+// files cannot be loaded.
+func LoadDir(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	var locales []*Locale
+	for _, entry := range entries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		l, err := LoadFile(filepath.Join(dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+		locales = append(locales, l)
+	}
+	for i, name := range names {
+		Register(name, locales[i])
+	}
+	return names, nil
+}
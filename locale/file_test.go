@@ -0,0 +1,116 @@
+package locale
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	for _, l := range []*Locale{EnUS, FrFR} {
+		data, err := json.Marshal(l.Def)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		have, err := FromJSON(data)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !reflect.DeepEqual(have.Def, l.Def) {
+			t.Errorf("\n have: %v \n want: %v", have.Def, l.Def)
+		}
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	l, err := LoadFile("testdata/json/en-GB.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if l.MonthDayOrder {
+		t.Errorf("expected day-month order")
+	}
+	if have := l.ZoneLocations["BST"]; have != "Europe/London" {
+		t.Errorf("\n have: %v \n want: %v", have, "Europe/London")
+	}
+	if have, _ := l.OrdinalSuffix(22); have != "nd" {
+		t.Errorf("\n have: %v \n want: %v", have, "nd")
+	}
+}
+
+func TestLoadFileError(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		err  string
+	}{
+		{"syntax", `{"MonthNamesWide": [`, "unexpected EOF"},
+		{"unknown", `{"MonthNames": []}`, "unknown field"},
+		{"invalid", `{"MonthNamesWide": ["January"]}`, "invalid number of month names"},
+	}
+
+	dir := t.TempDir()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			path := filepath.Join(dir, test.name+".json")
+			if err := os.WriteFile(path, []byte(test.data), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := LoadFile(path)
+			if err == nil {
+				t.Fatalf("expected error")
+			}
+			if !strings.Contains(err.Error(), test.err) || !strings.Contains(err.Error(), path) {
+				t.Errorf("\n have: %v \n want: %v", err, test.err)
+			}
+		})
+	}
+}
+
+func TestLoadDir(t *testing.T) {
+	names, err := LoadDir("testdata/json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	unregister(t, names...)
+	if !reflect.DeepEqual(names, []string{"en-GB"}) {
+		t.Errorf("\n have: %v \n want: %v", names, []string{"en-GB"})
+	}
+	l, ok := Lookup("en-GB")
+	if !ok {
+		t.Fatalf("locale not registered")
+	}
+	if l.MonthDayOrder {
+		t.Errorf("expected day-month order")
+	}
+	if have := Names(); !reflect.DeepEqual(have, []string{"en-GB", "en-US", "fr-FR"}) {
+		t.Errorf("\n have: %v", have)
+	}
+}
+
+func TestLoadDirError(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "xx-XX.json"), []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadDir(dir); err == nil {
+		t.Fatalf("expected error")
+	}
+	if _, ok := Lookup("xx-XX"); ok {
+		t.Errorf("locale should not be registered")
+	}
+}
+
+// unregister removes the locales with the names at the end of the test
+func unregister(t *testing.T, names ...string) {
+	t.Cleanup(func() {
+		mu.Lock()
+		defer mu.Unlock()
+		for _, name := range names {
+			delete(table, canonical(name))
+		}
+	})
+}
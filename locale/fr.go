@@ -30,6 +30,21 @@ var FrMonthNamesAbbr = []string{
 	"déc.",
 }
 
+var FrMonthNamesNarrow = []string{
+	"J",
+	"F",
+	"M",
+	"A",
+	"M",
+	"J",
+	"J",
+	"A",
+	"S",
+	"O",
+	"N",
+	"D",
+}
+
 var FrDayNamesWide = []string{
 	"dimanche",
 	"lundi",
@@ -50,20 +65,71 @@ var FrDayNamesAbbr = []string{
 	"sam.",
 }
 
+var FrDayNamesNarrow = []string{
+	"D",
+	"L",
+	"M",
+	"M",
+	"J",
+	"V",
+	"S",
+}
+
+var FrRelativeDayNames = map[string]int{
+	"avant-hier":   -2,
+	"hier":         -1,
+	"aujourd'hui":  0,
+	"demain":       1,
+	"après-demain": 2,
+}
+
+var FrUnitNames = String2D{
+	Seconds: []string{"seconde", "secondes"},
+	Minutes: []string{"minute", "minutes", "min"},
+	Hours:   []string{"heure", "heures"},
+	Days:    []string{"jour", "jours"},
+	Weeks:   []string{"semaine", "semaines"},
+	Months:  []string{"mois"},
+	Years:   []string{"an", "ans", "année", "années"},
+}
+
+var FrEraNames = String2D{
+	BC: []string{"av. J.-C.", "AEC"},
+	AD: []string{"ap. J.-C.", "EC"},
+}
+
+var FrOrdinalSuffixes = map[int]string{
+	0: "e",
+	1: "er",
+}
+
 var FrZonesShort = map[string]string{
 	"UTC": "+0000",
 }
 
 var FrFR = MustNew(Def{
-	MonthNamesWide: FrMonthNamesWide,
-	MonthNamesAbbr: FrMonthNamesAbbr,
-	DayNamesWide:   FrDayNamesWide,
-	DayNamesAbbr:   FrDayNamesAbbr,
-	ZoneNamesShort: FrZonesShort,
-	DateSep:        []string{"-", "/"},
-	TimeSep:        []string{":"},
-	HourSep:        []string{"h"},
-	DecimalSep:     ",",
-	DateTimeSep:    []string{"T"},
-	UTCFlags:       []string{"Z"},
+	MonthNamesWide:   FrMonthNamesWide,
+	MonthNamesAbbr:   FrMonthNamesAbbr,
+	MonthNamesNarrow: FrMonthNamesNarrow,
+	DayNamesWide:     FrDayNamesWide,
+	DayNamesAbbr:     FrDayNamesAbbr,
+	DayNamesNarrow:   FrDayNamesNarrow,
+	ZoneNamesShort:   FrZonesShort,
+	DateSep:          []string{"-", "/"},
+	TimeSep:          []string{":"},
+	HourSep:          []string{"h"},
+	DecimalSep:       ",",
+	DateTimeSep:      []string{"T"},
+	UTCFlags:         []string{"Z"},
+	RelativeDayNames: FrRelativeDayNames,
+	NextNames:        []string{"prochain", "prochaine"},
+	LastNames:        []string{"dernier", "dernière"},
+	FutureNames:      []string{"dans"},
+	PastNames:        []string{"il y a"},
+	UnitNames:        FrUnitNames,
+	RangeSep:         []string{"-", "–", "au", "à", "jusqu'à", "jusqu'au"},
+	RangeStartNames:  []string{"du", "de"},
+	DayStartNames:    []string{"le"},
+	OrdinalSuffixes:  FrOrdinalSuffixes,
+	EraNames:         FrEraNames,
 })
@@ -2,20 +2,32 @@ package locale
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"sync"
 )
 
 type String2D [][]string
 
 func (s String2D) Main(index int) string {
-	if len(s[index]) == 0 {
+	if index >= len(s) || len(s[index]) == 0 {
 		return ""
 	}
 	return s[index][0]
 }
 
+func (s String2D) All(index int) []string {
+	if index >= len(s) {
+		return nil
+	}
+	return s[index]
+}
+
 func (s String2D) Alt(index int) string {
+	if index >= len(s) {
+		return ""
+	}
 	switch len(s[index]) {
 	case 0:
 		return ""
@@ -33,21 +45,57 @@ const (
 	Midnight
 )
 
+const (
+	BC = iota
+	AD
+)
+
+const (
+	Seconds = iota
+	Minutes
+	Hours
+	Days
+	Weeks
+	Months
+	Years
+)
+
+// Def is the definition of a locale. It can be read from and written to
+// JSON with the encoding/json package using the names of the fields as keys.
+// Names are stored when the Locale is created, so options such as
+// FoldDiacritics must be set on the Def before calling New.
 type Def struct {
-	MonthDayOrder     bool
-	MonthNamesWide    []string
-	MonthNamesAbbr    []string
-	DayNamesWide      []string
-	DayNamesAbbr      []string
-	PeriodNamesAbbr   String2D
-	PeriodNamesNarrow String2D
-	ZoneNamesShort    map[string]string
-	DateSep           []string
-	TimeSep           []string
-	HourSep           []string
-	DecimalSep        string
-	DateTimeSep       []string
-	UTCFlags          []string
+	MonthDayOrder     bool              `json:",omitempty"`
+	MonthNamesWide    []string          `json:",omitempty"`
+	MonthNamesAbbr    []string          `json:",omitempty"`
+	MonthNamesNarrow  []string          `json:",omitempty"`
+	DayNamesWide      []string          `json:",omitempty"`
+	DayNamesAbbr      []string          `json:",omitempty"`
+	DayNamesShort     []string          `json:",omitempty"`
+	DayNamesNarrow    []string          `json:",omitempty"`
+	PeriodNamesAbbr   String2D          `json:",omitempty"`
+	PeriodNamesNarrow String2D          `json:",omitempty"`
+	ZoneNamesShort    map[string]string `json:",omitempty"`
+	ZoneLocations     map[string]string `json:",omitempty"`
+	DateSep           []string          `json:",omitempty"`
+	TimeSep           []string          `json:",omitempty"`
+	HourSep           []string          `json:",omitempty"`
+	DecimalSep        string            `json:",omitempty"`
+	DateTimeSep       []string          `json:",omitempty"`
+	UTCFlags          []string          `json:",omitempty"`
+	RelativeDayNames  map[string]int    `json:",omitempty"`
+	NextNames         []string          `json:",omitempty"`
+	LastNames         []string          `json:",omitempty"`
+	FutureNames       []string          `json:",omitempty"`
+	PastNames         []string          `json:",omitempty"`
+	UnitNames         String2D          `json:",omitempty"`
+	RangeSep          []string          `json:",omitempty"`
+	RangeStartNames   []string          `json:",omitempty"`
+	DayStartNames     []string          `json:",omitempty"`
+	OrdinalSuffixes   map[int]string    `json:",omitempty"`
+	EraNames          String2D          `json:",omitempty"`
+	FoldDiacritics    bool              `json:",omitempty"`
+	NumberingSystem   string            `json:",omitempty"`
 }
 
 type Locale struct {
@@ -55,8 +103,14 @@ type Locale struct {
 	MonthNum     map[string]int
 	DayNum       map[string]int
 	PeriodNum    map[string]int
+	UnitNum      map[string]int
 	Offsets      map[string]int
+	EraNum       map[string]int
 	DisplayNames map[string]string
+	zoneNames    map[string]string
+	zoneKeys     map[string]string
+	zero         rune
+	fold         bool
 }
 
 func New(def Def) (*Locale, error) {
@@ -65,10 +119,21 @@ func New(def Def) (*Locale, error) {
 		MonthNum:     make(map[string]int),
 		DayNum:       make(map[string]int),
 		PeriodNum:    make(map[string]int),
+		UnitNum:      make(map[string]int),
 		Offsets:      make(map[string]int),
+		EraNum:       make(map[string]int),
 		DisplayNames: make(map[string]string),
+		zoneNames:    make(map[string]string),
+		zoneKeys:     make(map[string]string),
+		fold:         def.FoldDiacritics,
 	}
 
+	zero, err := numberingZero(def.NumberingSystem)
+	if err != nil {
+		return nil, err
+	}
+	l.zero = zero
+
 	if len(def.MonthNamesAbbr) != 12 {
 		return nil, fmt.Errorf("invalid number of month names (abbreviated)")
 	}
@@ -105,6 +170,19 @@ func New(def Def) (*Locale, error) {
 		l.DisplayNames[wideKey] = wide
 	}
 
+	// Short and narrow names are only used when formatting. Names such as
+	// "Mo" or "Sa" are too easily found in other words to be used for
+	// parsing.
+	if len(def.MonthNamesNarrow) != 0 && len(def.MonthNamesNarrow) != 12 {
+		return nil, fmt.Errorf("invalid number of month names (narrow)")
+	}
+	if len(def.DayNamesNarrow) != 0 && len(def.DayNamesNarrow) != 7 {
+		return nil, fmt.Errorf("invalid number of day names (narrow)")
+	}
+	if len(def.DayNamesShort) != 0 && len(def.DayNamesShort) != 7 {
+		return nil, fmt.Errorf("invalid number of day names (short)")
+	}
+
 	for i, names := range def.PeriodNamesAbbr {
 		for _, name := range names {
 			nameKey := l.Key(name)
@@ -120,6 +198,22 @@ func New(def Def) (*Locale, error) {
 		}
 	}
 
+	for i, names := range def.EraNames {
+		for _, name := range names {
+			nameKey := l.Key(name)
+			l.EraNum[nameKey] = i
+			l.DisplayNames[nameKey] = name
+		}
+	}
+
+	for i, names := range def.UnitNames {
+		for _, name := range names {
+			nameKey := l.Key(name)
+			l.UnitNum[nameKey] = i
+			l.DisplayNames[nameKey] = name
+		}
+	}
+
 	for zone, offset := range def.ZoneNamesShort {
 		zoneKey := l.Key(zone)
 		runes := []rune(offset)
@@ -148,6 +242,8 @@ func New(def Def) (*Locale, error) {
 		offset := sign*hrs*3600 + min*60
 		l.Offsets[zoneKey] = offset
 		l.DisplayNames[zoneKey] = zone
+		l.zoneNames[l.Normalize(zone)] = zone
+		l.zoneKeys[zoneKey] = zone
 	}
 	for _, flag := range l.UTCFlags {
 		flagKey := l.Key(flag)
@@ -158,8 +254,37 @@ func New(def Def) (*Locale, error) {
 	return l, nil
 }
 
+// OrdinalSuffix returns the suffix used to write the day as an ordinal
+// number, such as "st" in "1st". Days that are not found use the suffix
+// for day zero. Returns false if the locale does not have ordinal
+// suffixes.
+func (l *Locale) OrdinalSuffix(day int) (string, bool) {
+	if s, ok := l.OrdinalSuffixes[day]; ok {
+		return s, true
+	}
+	s, ok := l.OrdinalSuffixes[0]
+	return s, ok
+}
+
+// LookupZone returns the name in ZoneNamesShort that matches the text and
+// the offset for that zone. Zone names are normalized and a name with the
+// same case is preferred. Otherwise, names are compared like other names
+// so that "mst" is "MST".
+func (l *Locale) LookupZone(text string) (string, string, bool) {
+	zone, ok := l.zoneNames[l.Normalize(text)]
+	if !ok {
+		zone, ok = l.zoneKeys[l.Key(text)]
+	}
+	if !ok {
+		return "", "", false
+	}
+	return zone, l.ZoneNamesShort[zone], true
+}
+
+// Key returns the text in the form used to look up names. The text is
+// normalized, periods are removed, and letters are made lowercase.
 func (l *Locale) Key(v string) string {
-	v = strings.ReplaceAll(v, ".", "")
+	v = strings.ReplaceAll(l.Normalize(v), ".", "")
 	return strings.ToLower(v)
 }
 
@@ -171,16 +296,46 @@ func MustNew(def Def) *Locale {
 	return l
 }
 
-var table = map[string]*Locale{
-	"en-US": EnUS,
-	"fr-FR": FrFR,
+var (
+	mu    sync.RWMutex
+	table = map[string]*Locale{
+		"en-US": EnUS,
+		"fr-FR": FrFR,
+	}
+)
+
+// Register makes a locale available by name to Lookup. Names that are
+// language tags are stored in their usual case, so "en_gb" is registered
+// as "en-GB" and replaces a locale already registered with that name.
+func Register(name string, l *Locale) {
+	if l == nil {
+		panic("locale: Register locale is nil")
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	table[canonical(name)] = l
 }
 
+// Lookup returns the locale registered with name.
 func Lookup(name string) (*Locale, bool) {
-	l, ok := table[name]
+	mu.RLock()
+	defer mu.RUnlock()
+	l, ok := table[canonical(name)]
 	return l, ok
 }
 
+// Names returns the names of all registered locales in sorted order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	var names []string
+	for name := range table {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
 func MustLookup(name string) *Locale {
 	l, ok := Lookup(name)
 	if !ok {
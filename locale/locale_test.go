@@ -0,0 +1,14 @@
+package locale
+
+import "testing"
+
+func TestDayNamesShort(t *testing.T) {
+	for _, name := range EnUS.DayNamesShort {
+		if _, ok := EnUS.DayNum[EnUS.Key(name)]; ok {
+			t.Errorf("short day name %v should not be used for parsing", name)
+		}
+	}
+	if have := EnUS.DayNum[EnUS.Key("Mon")]; have != 1 {
+		t.Errorf("\n have: %v \n want: %v", have, 1)
+	}
+}
@@ -0,0 +1,118 @@
+package locale
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Letters with a single accent and the letters without it, listed by the
+// combining mark for that accent.
+var accented = map[rune][2]string{
+	'\u0300': {"ÀÈÌÒÙàèìòùǸǹẀẁỲỳ", "AEIOUaeiouNnWwYy"},                         // grave
+	'\u0301': {"ÁÉÍÓÚÝáéíóúýĆćĹĺŃńŔŕŚśŹźǴǵẂẃ", "AEIOUYaeiouyCcLlNnRrSsZzGgWw"}, // acute
+	'\u0302': {"ÂÊÎÔÛâêîôûĈĉĜĝĤĥĴĵŜŝŴŵŶŷ", "AEIOUaeiouCcGgHhJjSsWwYy"},         // circumflex
+	'\u0303': {"ÃÑÕãñõĨĩŨũỸỹ", "ANOanoIiUuYy"},                                 // tilde
+	'\u0304': {"ĀāĒēĪīŌōŪū", "AaEeIiOoUu"},                                     // macron
+	'\u0306': {"ĂăĔĕĞğĬĭŎŏŬŭ", "AaEeGgIiOoUu"},                                 // breve
+	'\u0307': {"ĊċĖėĠġİŻż", "CcEeGgIZz"},                                       // dot above
+	'\u0308': {"ÄËÏÖÜäëïöüÿŸ", "AEIOUaeiouyY"},                                 // diaeresis
+	'\u030a': {"ÅåŮů", "AaUu"},                                                 // ring above
+	'\u030b': {"ŐőŰű", "OoUu"},                                                 // double acute
+	'\u030c': {"ČčĎďĚěŇňŘřŠšŤťŽžǍǎǏǐǑǒǓǔ", "CcDdEeNnRrSsTtZzAaIiOoUu"},         // caron
+	'\u0326': {"ȘșȚț", "SsTt"},                                                 // comma below
+	'\u0327': {"ÇçĢģĶķĻļŅņŖŗŞşŢţ", "CcGgKkLlNnRrSsTt"},                         // cedilla
+	'\u0328': {"ĄąĘęĮįŲų", "AaEeIiUu"},                                         // ogonek
+}
+
+// Letters that do not have a decomposition but are written without the
+// stroke when diacritics are folded.
+var stroked = map[rune]rune{
+	'Ø': 'O', 'ø': 'o',
+	'Ł': 'L', 'ł': 'l',
+	'Đ': 'D', 'đ': 'd',
+}
+
+// Typographic variants that are replaced with the plain character
+var variants = map[rune]rune{
+	'\u2010': '-',  // hyphen
+	'\u2011': '-',  // non-breaking hyphen
+	'\u2018': '\'', // left single quotation mark
+	'\u2019': '\'', // right single quotation mark
+	'\u02bc': '\'', // modifier letter apostrophe
+}
+
+// decompositions is a variable, and not set in init, so that it is ready
+// before the locales in this package are created.
+var decompositions = decompose(accented)
+
+func decompose(accented map[rune][2]string) map[rune][2]rune {
+	d := make(map[rune][2]rune)
+	for mark, letters := range accented {
+		from, to := []rune(letters[0]), []rune(letters[1])
+		if len(from) != len(to) {
+			panic(fmt.Sprintf("locale: accented letters for %U do not match", mark))
+		}
+		for i, ch := range from {
+			d[ch] = [2]rune{to[i], mark}
+		}
+	}
+	return d
+}
+
+// Normalize returns the text in the form used to compare names. Letters
+// with an accent are written as the letter followed by a combining mark so
+// that composed and decomposed text is the same. Spaces, such as the
+// non-breaking space, are replaced with a regular space and typographic
+// hyphens and apostrophes with their plain versions. When FoldDiacritics was
+// set on the Def given to New, accents are removed so that "fevrier" is the
+// same as "février". Changing it on the Locale afterwards has no effect
+// since the names have already been stored.
+//
+// This is not full Unicode normalization (NFC or NFD). Only Latin letters
+// with a single accent are decomposed so that the package does not need
+// the Unicode decomposition tables. Names in other scripts are compared as
+// they are written.
+func (l *Locale) Normalize(v string) string {
+	if isPlain(v) {
+		return v
+	}
+	var b strings.Builder
+	for _, ch := range v {
+		switch {
+		case unicode.IsSpace(ch):
+			ch = ' '
+		case variants[ch] != 0:
+			ch = variants[ch]
+		}
+		if d, ok := decompositions[ch]; ok {
+			b.WriteRune(d[0])
+			if !l.fold {
+				b.WriteRune(d[1])
+			}
+			continue
+		}
+		if l.fold {
+			if unicode.Is(unicode.Mn, ch) {
+				continue
+			}
+			if s, ok := stroked[ch]; ok {
+				ch = s
+			}
+		}
+		b.WriteRune(ch)
+	}
+	return b.String()
+}
+
+// isPlain checks if the text is ASCII without any spaces other than the
+// regular space.
+func isPlain(v string) bool {
+	for i := 0; i < len(v); i++ {
+		if v[i] >= utf8.RuneSelf || (v[i] != ' ' && unicode.IsSpace(rune(v[i]))) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,60 @@
+package locale
+
+import "testing"
+
+func TestKey(t *testing.T) {
+	def := FrFR.Def
+	def.FoldDiacritics = true
+	fold := MustNew(def)
+
+	tests := []struct {
+		in   string
+		key  string
+		fold string
+	}{
+		{"Jan.", "jan", "jan"},
+		{"f\u00e9vrier", "fe\u0301vrier", "fevrier"},
+		{"fe\u0301vrier", "fe\u0301vrier", "fevrier"},
+		{"F\u00c9VR.", "fe\u0301vr", "fevr"},
+		{"ao\u00fbt", "aou\u0302t", "aout"},
+		{"\u0141\u00f3d\u017a", "\u0142o\u0301dz\u0301", "lodz"},
+		{"a.\u00a0m.", "a m", "a m"},
+		{"av. J.\u2011C.", "av j-c", "av j-c"},
+		{"aujourd\u2019hui", "aujourd'hui", "aujourd'hui"},
+	}
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			if have := FrFR.Key(test.in); have != test.key {
+				t.Errorf("\n have: %q \n want: %q", have, test.key)
+			}
+			if have := fold.Key(test.in); have != test.fold {
+				t.Errorf("\n have: %q \n want: %q (folded)", have, test.fold)
+			}
+		})
+	}
+}
+
+func TestFoldDiacriticsAfterNew(t *testing.T) {
+	l := MustNew(FrFR.Def)
+	l.FoldDiacritics = true
+	if have := l.MonthNum[l.Key("f\u00e9vrier")]; have != 2 {
+		t.Errorf("\n have: %v \n want: 2", have)
+	}
+	if _, ok := l.MonthNum[l.Key("fevrier")]; ok {
+		t.Errorf("expected unfolded lookup")
+	}
+}
+
+func TestLookupZone(t *testing.T) {
+	zone, offset, ok := EnUS.LookupZone("MST")
+	if !ok || zone != "MST" || offset != "-0700" {
+		t.Errorf("\n have: %v %v %v", zone, offset, ok)
+	}
+	zone, offset, ok = EnUS.LookupZone("mst")
+	if !ok || zone != "MST" || offset != "-0700" {
+		t.Errorf("\n have: %v %v %v", zone, offset, ok)
+	}
+	if _, _, ok := EnUS.LookupZone("XYZ"); ok {
+		t.Errorf("expected unknown zone")
+	}
+}
@@ -0,0 +1,46 @@
+package locale
+
+import "fmt"
+
+// Numbering systems from the CLDR that use a run of ten decimal digits,
+// listed by the digit zero.
+var numberingSystems = map[string]rune{
+	"arab":     '٠',
+	"arabext":  '۰',
+	"bali":     '᭐',
+	"beng":     '০',
+	"deva":     '०',
+	"fullwide": '０',
+	"gujr":     '૦',
+	"guru":     '੦',
+	"khmr":     '០',
+	"knda":     '೦',
+	"laoo":     '໐',
+	"latn":     '0',
+	"limb":     '᥆',
+	"mlym":     '൦',
+	"mong":     '᠐',
+	"mymr":     '၀',
+	"orya":     '୦',
+	"tamldec":  '௦',
+	"telu":     '౦',
+	"thai":     '๐',
+	"tibt":     '༠',
+}
+
+func numberingZero(name string) (rune, error) {
+	if name == "" {
+		return '0', nil
+	}
+	zero, ok := numberingSystems[name]
+	if !ok {
+		return 0, fmt.Errorf("unknown numbering system: %v", name)
+	}
+	return zero, nil
+}
+
+// Zero returns the digit zero of the numbering system used by the locale.
+// The other digits follow it in order, so the digit for n is Zero() + n.
+func (l *Locale) Zero() rune {
+	return l.zero
+}
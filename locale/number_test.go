@@ -0,0 +1,20 @@
+package locale
+
+import "testing"
+
+func TestNumberingSystem(t *testing.T) {
+	if have := EnUS.Zero(); have != '0' {
+		t.Errorf("\n have: %q \n want: %q", have, '0')
+	}
+
+	def := EnUS.Def
+	def.NumberingSystem = "arab"
+	if have := MustNew(def).Zero(); have != '٠' {
+		t.Errorf("\n have: %q \n want: %q", have, '٠')
+	}
+
+	def.NumberingSystem = "roman"
+	if _, err := New(def); err == nil {
+		t.Errorf("expected error")
+	}
+}
@@ -0,0 +1,305 @@
+package locale
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Tag is a BCP 47 language tag such as "fr-CA" or "zh-Hant-TW". Extensions
+// and private use subtags, such as the "u-ca-gregory" in
+// "en-US-u-ca-gregory", are not used to find a locale and are dropped. The
+// zero value is the root locale.
+type Tag struct {
+	Language string
+	Script   string
+	Region   string
+	Variants []string
+}
+
+// ParseTag parses a BCP 47 language tag. Subtags can be separated by "-" or
+// "_" and are converted to their usual case, so "EN_us" is "en-US". The tags
+// "root" and "und" are the root locale.
+func ParseTag(s string) (Tag, error) {
+	parts := strings.FieldsFunc(s, func(r rune) bool { return r == '-' || r == '_' })
+	if len(parts) == 0 {
+		return Tag{}, fmt.Errorf("invalid language tag: %q", s)
+	}
+	var t Tag
+	lang := strings.ToLower(parts[0])
+	switch {
+	case lang == "root" || lang == "und":
+	case isAlpha(lang) && (len(lang) == 2 || len(lang) == 3 || (len(lang) >= 5 && len(lang) <= 8)):
+		t.Language = lang
+	default:
+		return Tag{}, fmt.Errorf("invalid language in tag: %q", s)
+	}
+
+	parts = parts[1:]
+	if len(parts) > 0 && len(parts[0]) == 4 && isAlpha(parts[0]) {
+		t.Script = strings.ToUpper(parts[0][:1]) + strings.ToLower(parts[0][1:])
+		parts = parts[1:]
+	}
+	if len(parts) > 0 && ((len(parts[0]) == 2 && isAlpha(parts[0])) || (len(parts[0]) == 3 && isDigits(parts[0]))) {
+		t.Region = strings.ToUpper(parts[0])
+		parts = parts[1:]
+	}
+	for len(parts) > 0 {
+		p := strings.ToLower(parts[0])
+		if len(p) == 1 && len(parts) > 1 {
+			// Start of an extension or private use
+			break
+		}
+		if !isAlphaNum(p) || !(len(p) >= 5 && len(p) <= 8 || len(p) == 4 && isDigits(p[:1])) {
+			return Tag{}, fmt.Errorf("invalid subtag '%v' in tag: %q", parts[0], s)
+		}
+		t.Variants = append(t.Variants, p)
+		parts = parts[1:]
+	}
+	return t, nil
+}
+
+func (t Tag) String() string {
+	if t.Language == "" {
+		return "root"
+	}
+	parts := []string{t.Language}
+	if t.Script != "" {
+		parts = append(parts, t.Script)
+	}
+	if t.Region != "" {
+		parts = append(parts, t.Region)
+	}
+	parts = append(parts, t.Variants...)
+	return strings.Join(parts, "-")
+}
+
+// Parent returns the tag with the last subtag removed. The parent of a tag
+// with only a language is the root locale. Returns false if the tag is the
+// root locale.
+func (t Tag) Parent() (Tag, bool) {
+	switch {
+	case len(t.Variants) > 0:
+		t.Variants = t.Variants[:len(t.Variants)-1]
+	case t.Region != "":
+		t.Region = ""
+	case t.Script != "":
+		t.Script = ""
+	case t.Language != "":
+		t.Language = ""
+	default:
+		return Tag{}, false
+	}
+	return t, true
+}
+
+// Fallbacks returns the names of the locales to try for the tag, from the
+// tag itself to the root locale. For example, "fr-CA" returns "fr-CA",
+// "fr", and "root".
+func (t Tag) Fallbacks() []string {
+	names := []string{t.String()}
+	for p, ok := t.Parent(); ok; p, ok = p.Parent() {
+		names = append(names, p.String())
+	}
+	return names
+}
+
+// LookupTag returns the registered locale that best matches a BCP 47
+// language tag. Each name from Fallbacks is tried in order, except for
+// root. When none of those are registered, a locale with the same language
+// and script is used, such as "fr-FR" for "fr-CA", but not "zh-Hans-CN" for
+// "zh-Hant-TW". If there is none, the locale registered as "root" is used,
+// if any.
+func LookupTag(tag string) (*Locale, bool) {
+	t, err := ParseTag(tag)
+	if err != nil {
+		return nil, false
+	}
+	mu.RLock()
+	defer mu.RUnlock()
+	if l, ok := lookupLanguage(t); ok {
+		return l, true
+	}
+	return root()
+}
+
+// Match returns the registered locale that best matches the value of an
+// HTTP Accept-Language header, such as "fr-CA,fr;q=0.9,en;q=0.8". The
+// languages are tried like LookupTag from the highest quality to the
+// lowest and the root locale is only used when none of them match.
+// Languages with a quality of zero, wildcards, and entries that cannot be
+// parsed are skipped. Returns false if no locale matches.
+func Match(acceptLanguage string) (*Locale, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	for _, tag := range parseAcceptLanguage(acceptLanguage) {
+		t, err := ParseTag(tag)
+		if err != nil {
+			continue
+		}
+		if l, ok := lookupLanguage(t); ok {
+			return l, true
+		}
+	}
+	return root()
+}
+
+// Scripts that are used for a language when the tag does not have one.
+// Some languages use a different script in some regions.
+var likelyScripts = map[string]string{
+	"ar": "Arab", "de": "Latn", "el": "Grek", "en": "Latn", "es": "Latn",
+	"fa": "Arab", "fr": "Latn", "he": "Hebr", "hi": "Deva", "it": "Latn",
+	"ja": "Jpan", "ko": "Kore", "nl": "Latn", "pl": "Latn", "pt": "Latn",
+	"ru": "Cyrl", "sr": "Cyrl", "sv": "Latn", "th": "Thai", "tr": "Latn",
+	"uk": "Cyrl", "zh": "Hans",
+	"zh-HK": "Hant", "zh-MO": "Hant", "zh-TW": "Hant",
+}
+
+// Regions that are used for a language and script when there is no
+// registered locale for the region in the tag.
+var likelyRegions = map[string]string{
+	"ar-Arab": "EG", "de-Latn": "DE", "el-Grek": "GR", "en-Latn": "US",
+	"es-Latn": "ES", "fa-Arab": "IR", "fr-Latn": "FR", "he-Hebr": "IL",
+	"hi-Deva": "IN", "it-Latn": "IT", "ja-Jpan": "JP", "ko-Kore": "KR",
+	"nl-Latn": "NL", "pl-Latn": "PL", "pt-Latn": "BR", "ru-Cyrl": "RU",
+	"sr-Cyrl": "RS", "sr-Latn": "RS", "sv-Latn": "SE", "th-Thai": "TH",
+	"tr-Latn": "TR", "uk-Cyrl": "UA", "zh-Hans": "CN", "zh-Hant": "TW",
+}
+
+// script returns the script of the tag or the likely script for the
+// language if it does not have one.
+func (t Tag) script() string {
+	if t.Script != "" {
+		return t.Script
+	}
+	if s, ok := likelyScripts[t.Language+"-"+t.Region]; ok {
+		return s
+	}
+	return likelyScripts[t.Language]
+}
+
+// lookupLanguage finds the locale for a tag without using the root locale.
+// When no name from Fallbacks is registered, the locales with the same
+// language and script are candidates. The one in the region of the tag is
+// used, then the one in the likely region for the language, such as
+// "en-US" for "en-GB", and then the only candidate if there is just one.
+// The lock must be held.
+func lookupLanguage(t Tag) (*Locale, bool) {
+	names := t.Fallbacks()
+	for _, name := range names[:len(names)-1] {
+		if l, ok := table[name]; ok {
+			return l, true
+		}
+	}
+	if t.Language == "" {
+		return nil, false
+	}
+	script := t.script()
+	region := likelyRegions[t.Language+"-"+script]
+	var candidates []string
+	for name := range table {
+		nt, err := ParseTag(name)
+		if err != nil || nt.Language != t.Language || nt.script() != script {
+			continue
+		}
+		candidates = append(candidates, name)
+	}
+	// Sorted so that the same locale is used when more than one name is in
+	// the likely region, such as "zh-CN" and "zh-Hans-CN"
+	sort.Strings(candidates)
+	for _, r := range []string{t.Region, region} {
+		for _, name := range candidates {
+			if nt, _ := ParseTag(name); r != "" && nt.Region == r {
+				return table[name], true
+			}
+		}
+	}
+	if len(candidates) == 1 {
+		return table[candidates[0]], true
+	}
+	return nil, false
+}
+
+// canonical returns the name used in the registry. Names that are language
+// tags are written in their usual case with "-" between subtags so that
+// "en_us" and "en-US" are the same.
+func canonical(name string) string {
+	t, err := ParseTag(name)
+	if err != nil || !strings.EqualFold(strings.ReplaceAll(name, "_", "-"), t.String()) {
+		return name
+	}
+	return t.String()
+}
+
+// root returns the locale registered as "root". The lock must be held.
+func root() (*Locale, bool) {
+	l, ok := table["root"]
+	return l, ok
+}
+
+// parseAcceptLanguage returns the language tags in an Accept-Language
+// header sorted by quality.
+func parseAcceptLanguage(header string) []string {
+	type entry struct {
+		tag string
+		q   float64
+	}
+	var entries []entry
+	for _, item := range strings.Split(header, ",") {
+		params := strings.Split(item, ";")
+		tag := strings.TrimSpace(params[0])
+		if tag == "" || tag == "*" {
+			continue
+		}
+		q := 1.0
+		for _, param := range params[1:] {
+			k, v, ok := strings.Cut(strings.TrimSpace(param), "=")
+			if !ok || strings.TrimSpace(k) != "q" {
+				continue
+			}
+			var err error
+			if q, err = strconv.ParseFloat(strings.TrimSpace(v), 64); err != nil || q < 0 || q > 1 {
+				q = 0
+			}
+		}
+		if q > 0 {
+			entries = append(entries, entry{tag, q})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].q > entries[j].q
+	})
+	tags := make([]string, len(entries))
+	for i, e := range entries {
+		tags[i] = e.tag
+	}
+	return tags
+}
+
+func isAlpha(s string) bool {
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+func isDigits(s string) bool {
+	for _, ch := range s {
+		if ch < '0' || ch > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+func isAlphaNum(s string) bool {
+	for _, ch := range s {
+		if !(ch >= 'a' && ch <= 'z' || ch >= 'A' && ch <= 'Z' || ch >= '0' && ch <= '9') {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,226 @@
+package locale
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		in   string
+		want Tag
+		out  string
+	}{
+		{"fr", Tag{Language: "fr"}, "fr"},
+		{"fr-CA", Tag{Language: "fr", Region: "CA"}, "fr-CA"},
+		{"EN_us", Tag{Language: "en", Region: "US"}, "en-US"},
+		{"es-419", Tag{Language: "es", Region: "419"}, "es-419"},
+		{"zh-hant-tw", Tag{Language: "zh", Script: "Hant", Region: "TW"}, "zh-Hant-TW"},
+		{"sl-IT-nedis", Tag{Language: "sl", Region: "IT", Variants: []string{"nedis"}}, "sl-IT-nedis"},
+		{"de-CH-1996", Tag{Language: "de", Region: "CH", Variants: []string{"1996"}}, "de-CH-1996"},
+		{"en-US-u-ca-gregory", Tag{Language: "en", Region: "US"}, "en-US"},
+		{"en-x-private", Tag{Language: "en"}, "en"},
+		{"root", Tag{}, "root"},
+		{"und", Tag{}, "root"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.in, func(t *testing.T) {
+			have, err := ParseTag(test.in)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(have, test.want) {
+				t.Errorf("\n have: %#v \n want: %#v", have, test.want)
+			}
+			if have.String() != test.out {
+				t.Errorf("\n have: %v \n want: %v", have.String(), test.out)
+			}
+		})
+	}
+}
+
+func TestParseTagError(t *testing.T) {
+	tests := []string{"", "-", "f", "fr1", "fr-C", "en-US-abc", "x-private"}
+	for _, test := range tests {
+		t.Run(test, func(t *testing.T) {
+			if tag, err := ParseTag(test); err == nil {
+				t.Errorf("expected error, have: %v", tag)
+			}
+		})
+	}
+}
+
+func TestFallbacks(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want []string
+	}{
+		{"fr-CA", []string{"fr-CA", "fr", "root"}},
+		{"zh-Hant-TW", []string{"zh-Hant-TW", "zh-Hant", "zh", "root"}},
+		{"sl-IT-nedis", []string{"sl-IT-nedis", "sl-IT", "sl", "root"}},
+		{"root", []string{"root"}},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			tag, err := ParseTag(test.tag)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if have := tag.Fallbacks(); !reflect.DeepEqual(have, test.want) {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+}
+
+func TestLookupTag(t *testing.T) {
+	tests := []struct {
+		tag  string
+		want *Locale
+	}{
+		{"en-US", EnUS},
+		{"en_us", EnUS},
+		{"en-GB", EnUS},
+		{"en", EnUS},
+		{"fr", FrFR},
+		{"fr-CA", FrFR},
+		{"fr-Latn-CA-u-ca-gregory", FrFR},
+		{"de-DE", nil},
+		{"root", nil},
+		{"not a tag", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			have, ok := LookupTag(test.tag)
+			if ok != (test.want != nil) || have != test.want {
+				t.Errorf("unexpected locale")
+			}
+		})
+	}
+}
+
+func TestLookupTagRoot(t *testing.T) {
+	withRoot(t, EnUS)
+	for _, tag := range []string{"de-DE", "root", "und"} {
+		if have, _ := LookupTag(tag); have != EnUS {
+			t.Errorf("%v: expected root locale", tag)
+		}
+	}
+}
+
+func TestMatch(t *testing.T) {
+	tests := []struct {
+		header string
+		want   *Locale
+	}{
+		{"fr-CA,fr;q=0.9,en;q=0.8", FrFR},
+		{"en-GB, en;q=0.9", EnUS},
+		{"de-DE,de;q=0.9,en;q=0.5", EnUS},
+		{"de-DE, fr;q=0.4, en;q=0.5", EnUS},
+		{"en;q=0.4, fr", FrFR},
+		{"fr;q=0, en", EnUS},
+		{"*, fr;q=0.5", FrFR},
+		{"??, fr;q=0.5", FrFR},
+		{"de-DE", nil},
+		{"", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.header, func(t *testing.T) {
+			have, ok := Match(test.header)
+			if ok != (test.want != nil) || have != test.want {
+				t.Errorf("unexpected locale")
+			}
+		})
+	}
+}
+
+func TestMatchRoot(t *testing.T) {
+	withRoot(t, FrFR)
+	if have, _ := Match("de-DE, en;q=0.1"); have != EnUS {
+		t.Errorf("expected en-US before root locale")
+	}
+	if have, _ := Match("de-DE"); have != FrFR {
+		t.Errorf("expected root locale")
+	}
+}
+
+// withRoot registers l as the root locale until the end of the test
+func withRoot(t *testing.T, l *Locale) {
+	Register("root", l)
+	unregister(t, "root")
+}
+
+func TestLookupTagScript(t *testing.T) {
+	zh := MustNew(EnUS.Def)
+	Register("zh-Hans-CN", zh)
+	unregister(t, "zh-Hans-CN")
+
+	tests := []struct {
+		tag  string
+		want *Locale
+	}{
+		{"zh", zh},
+		{"zh-CN", zh},
+		{"zh-Hans-SG", zh},
+		{"zh-Hant-TW", nil},
+		{"zh-TW", nil},
+		{"zh-HK", nil},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			have, ok := LookupTag(test.tag)
+			if ok != (test.want != nil) || have != test.want {
+				t.Errorf("unexpected locale")
+			}
+		})
+	}
+}
+
+func TestLookupTagRegion(t *testing.T) {
+	au := MustNew(EnUS.Def)
+	Register("en-AU", au)
+	unregister(t, "en-AU")
+
+	tests := []struct {
+		tag  string
+		want *Locale
+	}{
+		{"en", EnUS},
+		{"en-GB", EnUS},
+		{"en-AU", au},
+		{"en-Latn-AU", au},
+	}
+	for _, test := range tests {
+		t.Run(test.tag, func(t *testing.T) {
+			if have, _ := LookupTag(test.tag); have != test.want {
+				t.Errorf("unexpected locale")
+			}
+		})
+	}
+}
+
+func TestRegisterCase(t *testing.T) {
+	gb := MustNew(EnUS.Def)
+	Register("en_gb", gb)
+	unregister(t, "en-GB")
+	nz := MustNew(EnUS.Def)
+	Register("EN-nz", nz)
+	unregister(t, "en-NZ")
+
+	if have, _ := Lookup("en-GB"); have != gb {
+		t.Errorf("expected en-GB")
+	}
+	if have, _ := LookupTag("en-nz"); have != nz {
+		t.Errorf("expected en-NZ")
+	}
+	if have := Names(); !reflect.DeepEqual(have, []string{"en-GB", "en-NZ", "en-US", "fr-FR"}) {
+		t.Errorf("\n have: %v", have)
+	}
+
+	other := MustNew(EnUS.Def)
+	Register("en-GB", other)
+	if have, _ := Lookup("en_GB"); have != other {
+		t.Errorf("expected the last locale registered")
+	}
+}
@@ -2,9 +2,13 @@ package ptime
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/blackchip-org/ptime/locale"
 )
@@ -12,8 +16,10 @@ import (
 type Parsed struct {
 	Weekday     string `json:",omitempty"`
 	Year        string `json:",omitempty"`
+	Era         string `json:",omitempty"`
 	Month       string `json:",omitempty"`
 	Day         string `json:",omitempty"`
+	Week        string `json:",omitempty"`
 	Hour        string `json:",omitempty"`
 	Minute      string `json:",omitempty"`
 	Second      string `json:",omitempty"`
@@ -21,10 +27,15 @@ type Parsed struct {
 	Period      string `json:",omitempty"`
 	Zone        string `json:",omitempty"`
 	Offset      string `json:",omitempty"`
+	Location    string `json:",omitempty"`
 	DateSep     string `json:",omitempty"`
 	TimeSep     string `json:",omitempty"`
 	DateTimeSep string `json:",omitempty"`
 	HourSep     string `json:",omitempty"`
+	Standard    string `json:",omitempty"`
+
+	Relative     string `json:",omitempty"`
+	RelativeUnit string `json:",omitempty"`
 }
 
 func (p Parsed) String() string {
@@ -83,25 +94,84 @@ func (d dateOrder) String() string {
 	return "unknown"
 }
 
+// Parser holds the configuration used for parsing. Once configured, a
+// Parser can be used by multiple goroutines at the same time. Options
+// should not be changed while a parse is in progress.
 type Parser struct {
-	loc       *locale.Locale
+	loc          *locale.Locale
+	Trace        bool
+	ISO8601      bool
+	RFC          bool
+	TwoDigitYear TwoDigitYear
+	Resolve      Resolve
+	WeekdayMode  WeekdayMode
+	Normalize    bool
+}
+
+// parseContext holds the state for a single call to the parser
+type parseContext struct {
+	*Parser
 	tokens    []Token
 	tok       Token
+	end       int
 	idx       int
 	parsed    Parsed
-	Trace     bool
 	state     state
 	dateOrder dateOrder
+	orderRule string
+	forced    dateOrder
 	parseOne  bool
+	relSign   int
+	relAmount string
+	relFirst  Token
+	relLast   Token
+	ordinals  map[int]Token
+	text      string
+	src       string
+	offsets   []int
+	fields    map[string]Token
+	discarded []Token
 }
 
 func NewParser(l *locale.Locale) *Parser {
 	return &Parser{loc: l}
 }
 
-func (p *Parser) parse(text string) (Parsed, error) {
+func (p *Parser) newContext(s state, parseOne bool) *parseContext {
+	return &parseContext{Parser: p, state: s, parseOne: parseOne}
+}
+
+func (p *parseContext) parse(text string) (Parsed, error) {
+	p.src = text
+	text, p.offsets = normalize(text)
+	parsed, err := p.parseNormalized(text)
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.Token = p.source(perr.Token)
+	}
+	return parsed, err
+}
+
+// parseNormalized parses the text after it was normalized. Positions in
+// tokens are in the normalized text until they are reported.
+func (p *parseContext) parseNormalized(text string) (Parsed, error) {
 	p.trace("state: %v", p.state)
-	p.tokens = Scan(text)
+	if p.RFC && p.state == unknown {
+		if parsed, ok := parseRFC(p.loc, text); ok {
+			p.trace("is %v", parsed.Standard)
+			return parsed, nil
+		}
+	}
+	if p.ISO8601 {
+		return p.parseISO(text)
+	}
+	p.end = len(text) + 1
+	p.text = text
+	tokens, err := p.removeOrdinals(scan(text))
+	if err != nil {
+		return p.parsed, err
+	}
+	p.tokens = tokens
 
 	if len(p.tokens) == 0 {
 		return Parsed{}, nil
@@ -109,8 +179,6 @@ func (p *Parser) parse(text string) (Parsed, error) {
 
 	p.idx = -1
 	p.tok = p.tokens[0]
-	p.parsed = Parsed{}
-	p.dateOrder = unknownOrder
 
 	for p.tok.Type != End {
 		var err error
@@ -128,28 +196,50 @@ func (p *Parser) parse(text string) (Parsed, error) {
 			return p.parsed, err
 		}
 	}
+	if err := p.endRelative(); err != nil {
+		return p.parsed, err
+	}
 	return p.parsed, nil
 }
 
 func (p *Parser) Parse(text string) (Parsed, error) {
-	p.state = unknown
-	p.parseOne = false
-	return p.parse(text)
+	return p.newContext(unknown, false).parse(text)
 }
 
 func (p *Parser) ParseDate(text string) (Parsed, error) {
-	p.state = parsingDate
-	p.parseOne = true
-	return p.parse(text)
+	return p.newContext(parsingDate, true).parse(text)
 }
 
 func (p *Parser) ParseTime(text string) (Parsed, error) {
-	p.state = parsingTime
-	p.parseOne = true
-	return p.parse(text)
+	return p.newContext(parsingTime, true).parse(text)
 }
 
-func (p *Parser) parseText() error {
+func (p *parseContext) parseText() error {
+	if ok, err := p.parseRelative(); ok {
+		return err
+	}
+	if ok, err := p.parseLocation(); ok {
+		return err
+	}
+	if era, n := p.lookaheadEra(p.idx); n > 0 {
+		return p.parseEraText(era, n)
+	}
+	if p.isTimeOfDay() {
+		p.trace("is time of day")
+		p.parsed.Period, _ = lookupPeriod(p.loc, p.tok.Val)
+		p.mark("Period", p.tok)
+		return nil
+	}
+	if n := p.lookaheadDayStart(); n > 0 {
+		p.trace("is day start")
+		for i := 0; i < n; i++ {
+			if i > 0 {
+				p.next()
+			}
+			p.discard()
+		}
+		return nil
+	}
 	if p.state == unknown {
 		p.state = parsingDate
 	}
@@ -158,6 +248,8 @@ func (p *Parser) parseText() error {
 			if day, ok := lookupDay(p.loc, p.tok.Val); ok {
 				p.trace("is weekday")
 				p.parsed.Weekday = day
+				p.mark("Weekday", p.tok)
+				p.endRelativeWeekday()
 				return nil
 			}
 		}
@@ -165,6 +257,7 @@ func (p *Parser) parseText() error {
 			if mon, ok := lookupMonth(p.loc, p.tok.Val); ok {
 				p.trace("is month")
 				p.parsed.Month = mon
+				p.mark("Month", p.tok)
 				return nil
 			}
 		}
@@ -172,6 +265,7 @@ func (p *Parser) parseText() error {
 			p.trace("is date time separator")
 			p.changeState(parsingTime)
 			p.parsed.DateTimeSep = p.tok.Val
+			p.mark("DateTimeSep", p.tok)
 			return nil
 		}
 	}
@@ -183,47 +277,135 @@ func (p *Parser) parseText() error {
 			period, ok := lookupPeriod(p.loc, p.tok.Val)
 			if ok {
 				p.trace("is period")
+				if err := p.checkPeriod(); err != nil {
+					return err
+				}
 				p.parsed.Period = string(period)
+				p.mark("Period", p.tok)
 				p.changeState(parsingZone)
 				return nil
 			}
 		}
-		if _, ok := p.loc.ZoneNamesShort[p.tok.Val]; ok {
+		if _, _, ok := p.loc.LookupZone(p.tok.Val); ok {
 			p.changeState(parsingZone)
 		}
 		if inSet(p.tok.Val, p.loc.UTCFlags) {
 			p.trace("is UTC")
 			p.parsed.Zone = p.tok.Val
 			p.parsed.Offset = "+0000"
+			p.mark("Zone", p.tok)
+			p.mark("Offset", p.tok)
 			return nil
 		}
 	}
 	if p.state == parsingZone {
 		p.trace("is zone")
-		var offset string
-		var ok bool
-
-		offset, ok = p.loc.ZoneNamesShort[p.tok.Val]
+		zone, offset, ok := p.loc.LookupZone(p.tok.Val)
 		if !ok {
 			p.trace("zone not recognized")
+			p.discard()
 			return nil
 		}
-		p.parsed.Zone = p.tok.Val
+		p.parsed.Zone = zone
+		p.mark("Zone", p.tok)
 		if p.parsed.Offset != "" && p.parsed.Offset != offset {
-			return p.err("time zone '%v' does not match given offset '%v'", p.tok.Val, p.parsed.Offset)
+			return p.err(ErrZoneMismatch, "Zone", "time zone '%v' does not match given offset '%v'", p.tok.Val, p.parsed.Offset)
+		}
+		if p.parsed.Offset == "" {
+			p.mark("Offset", p.tok)
 		}
 		p.parsed.Offset = offset
 		return nil
 	}
 
-	return p.err("unexpected text: %v", p.tok.Val)
+	return p.err(ErrUnexpectedText, "", "unexpected text: %v", p.tok.Val)
 }
 
-func (p *Parser) parseNumber() error {
+// lookaheadDayStart checks if the current token starts one of the words
+// in DayStartNames, such as the "le" in "le 1er mars", that comes before a
+// day and is ignored. Returns the number of tokens used by the word.
+func (p *parseContext) lookaheadDayStart() int {
+	if (p.state != unknown && p.state != parsingDate) || p.parsed.Day != "" {
+		return 0
+	}
+	for _, name := range p.loc.DayStartNames {
+		if n := matchTokens(p.loc, p.tokens[p.idx:], name); n > 0 && p.lookahead(n).Type == Number {
+			return n
+		}
+	}
+	return 0
+}
+
+// isTimeOfDay checks if the current token is a period, such as "noon" or
+// "midnight", that is a complete time on its own.
+func (p *parseContext) isTimeOfDay() bool {
+	if p.parsed.Hour != "" || p.parsed.Period != "" || p.state == parsingTime {
+		return false
+	}
+	n, ok := p.loc.PeriodNum[p.loc.Key(p.tok.Val)]
+	return ok && (n == locale.Noon || n == locale.Midnight)
+}
+
+// checkPeriod checks that the hour can be used with the period in the
+// current token. Only the hours 1 to 12 can be used with AM and PM and only
+// 12 can be used with noon and midnight.
+func (p *parseContext) checkPeriod() error {
+	if p.parsed.Hour == "" {
+		return nil
+	}
+	h, _ := strconv.Atoi(p.parsed.Hour)
+	if _, ok := clockHour(p.loc.PeriodNum[p.loc.Key(p.tok.Val)], h, true); !ok {
+		return p.err(ErrInvalidHour, "Hour", "invalid hour for %v: %v", p.tok.Val, p.parsed.Hour)
+	}
+	return nil
+}
+
+// parseEraText parses an era name that comes before the year, such as the
+// "AD" in "AD 1066".
+func (p *parseContext) parseEraText(era int, n int) error {
+	if err := p.parseEra(era, n); err != nil {
+		return err
+	}
+	if p.state == unknown {
+		p.changeState(parsingDate)
+	}
+	if la := p.lookahead(1); la.Type == Number {
+		p.next()
+		return p.parseEraYear()
+	}
+	if p.parsed.Year == "" {
+		return p.err(ErrInvalidYear, "Year", "missing year for era")
+	}
+	return nil
+}
+
+func (p *parseContext) parseNumber() error {
+	if _, ok := lookupUnit(p.loc, p.lookahead(1).Val); ok && p.relAmount == "" {
+		p.trace("is relative amount")
+		p.relAmount = p.tok.Val
+		p.markRelative(p.tok)
+		return nil
+	}
+	if era, n := p.lookaheadEra(p.idx + 1); n > 0 && (p.state != parsingTime || !p.parseOne) {
+		if p.state == unknown {
+			p.changeState(parsingDate)
+		}
+		if err := p.parseEraYear(); err != nil {
+			return err
+		}
+		p.next()
+		return p.parseEra(era, n)
+	}
+	if _, ok := p.ordinals[p.tok.Pos]; ok {
+		return p.parseOrdinal()
+	}
 	if p.state == unknown {
 		la := p.lookahead(1)
+		_, laIsPeriod := lookupPeriod(p.loc, la.Val)
 		if la.Type == Indicator && (inSet(la.Val, p.loc.TimeSep) || inSet(la.Val, p.loc.HourSep)) {
 			p.changeState(parsingTime)
+		} else if la.Type == Text && laIsPeriod {
+			p.changeState(parsingTime)
 		} else {
 			p.changeState(parsingDate)
 		}
@@ -242,18 +424,19 @@ func (p *Parser) parseNumber() error {
 	}
 	if p.state == parsingZone {
 		p.changeState(done)
-		return p.parseYear4()
+		return p.parseFullYear()
 	}
-	return p.err("extra number: %v", p.tok.Val)
+	return p.err(ErrExtraNumber, "", "extra number: %v", p.tok.Val)
 }
 
-func (p *Parser) parseNumberDate() error {
+func (p *parseContext) parseNumberDate() error {
 	sep := p.parsed.DateSep
 	if sep == "" {
 		la := p.lookahead(1)
 		if la.Type == Indicator {
 			if inSet(la.Val, p.loc.DateSep) {
 				sep = la.Val
+				p.mark("DateSep", la)
 			}
 		} else {
 			sep = " "
@@ -264,13 +447,38 @@ func (p *Parser) parseNumberDate() error {
 	return p.parseDate()
 }
 
-func (p *Parser) parseNumberTime() error {
+// parseOrdinal parses a number that had an ordinal suffix. It can only be
+// the day and when the month has not yet been seen, the day comes first.
+func (p *parseContext) parseOrdinal() error {
+	if p.state == unknown {
+		p.changeState(parsingDate)
+	}
+	if p.state != parsingDate || p.parsed.Day != "" {
+		return p.err(ErrInvalidDay, "Day", "unexpected ordinal day: %v", p.tok.Val)
+	}
+	if p.dateOrder == unknownOrder && p.forced == unknownOrder && p.parsed.Month == "" {
+		p.dateOrder = dayMonthYearOrder
+		p.orderRule = ruleOrdinal
+		p.trace("order: %v (ordinal)", p.dateOrder)
+	}
+	if err := p.parseNumberDate(); err != nil {
+		return err
+	}
+	if p.parsed.Day != p.tok.Val {
+		return p.err(ErrInvalidDay, "Day", "unexpected ordinal day: %v", p.tok.Val)
+	}
+	p.mark("Day", p.span(p.tok, p.ordinals[p.tok.Pos]))
+	return nil
+}
+
+func (p *parseContext) parseNumberTime() error {
 	sep := p.parsed.TimeSep
 	if sep == "" && p.parsed.HourSep == "" {
 		la := p.lookahead(1)
 		if la.Val != "" {
 			if inSet(la.Val, p.loc.TimeSep) {
 				sep = la.Val
+				p.mark("TimeSep", la)
 			} else {
 				if inSet(la.Val, p.loc.HourSep) {
 					sep = ""
@@ -283,7 +491,10 @@ func (p *Parser) parseNumberTime() error {
 	return p.parseTime()
 }
 
-func (p *Parser) parseIndicator() error {
+func (p *parseContext) parseIndicator() error {
+	if p.signedYear() {
+		return p.parseSignedYear()
+	}
 	if p.state == parsingDate && p.tok.Val == p.parsed.DateSep {
 		p.next()
 		return p.parseDate()
@@ -303,10 +514,11 @@ func (p *Parser) parseIndicator() error {
 		}
 	}
 	p.trace("discarding")
+	p.discard()
 	return nil
 }
 
-func (p *Parser) changeState(newState state) {
+func (p *parseContext) changeState(newState state) {
 	if p.parseOne {
 		if newState != parsingZone {
 			newState = done
@@ -318,8 +530,13 @@ func (p *Parser) changeState(newState state) {
 	p.state = newState
 }
 
-func (p *Parser) parseDate() error {
+func (p *parseContext) parseDate() error {
 	delim := p.parsed.DateSep
+	if p.dateOrder == unknownOrder && p.forced != unknownOrder {
+		p.dateOrder = p.forced
+		p.orderRule = ruleAlternate
+		p.trace("order: %v (forced)", p.dateOrder)
+	}
 	if p.dateOrder == unknownOrder {
 		la1 := p.lookahead(1)
 		_, la1IsMonth := lookupMonth(p.loc, la1.Val)
@@ -329,16 +546,22 @@ func (p *Parser) parseDate() error {
 		switch {
 		case delim == "-" && la2IsMonth:
 			p.dateOrder = dayMonthYearOrder
+			p.orderRule = ruleMonthName
 		case delim == "-" && la2.Type == Number && len(la2.Val) == 3:
 			p.dateOrder = yearDayOrder
+			p.orderRule = ruleOrdinalDay
 		case delim == "-":
 			p.dateOrder = yearMonthDayOrder
+			p.orderRule = ruleSeparator
 		case la1IsMonth:
 			p.dateOrder = dayMonthYearOrder
+			p.orderRule = ruleMonthName
 		case p.loc.MonthDayOrder:
 			p.dateOrder = monthDayYearOrder
+			p.orderRule = ruleLocale
 		default:
 			p.dateOrder = dayMonthYearOrder
+			p.orderRule = ruleLocale
 		}
 		p.trace("order: %v", p.dateOrder)
 	}
@@ -352,12 +575,17 @@ func (p *Parser) parseDate() error {
 	case monthDayYearOrder:
 		return p.parseMonthDayYear()
 	}
-	return p.err("unexpected '%v' in date", p.tok.Val)
+	return p.err(ErrInvalidDate, "", "unexpected '%v' in date", p.tok.Val)
 }
 
-func (p *Parser) parseYearMonthDay() error {
+func (p *parseContext) parseYearMonthDay() error {
 	if p.parsed.Year == "" {
-		return p.parseYear4()
+		// A two digit year is only considered when looking for alternate
+		// interpretations
+		if p.forced != unknownOrder {
+			return p.parseYear()
+		}
+		return p.parseFullYear()
 	}
 	if p.parsed.Month == "" {
 		return p.parseMonth()
@@ -365,20 +593,20 @@ func (p *Parser) parseYearMonthDay() error {
 	if p.parsed.Day == "" {
 		return p.parseDay()
 	}
-	return p.err("pass parseYearDayMonth")
+	return p.err(ErrInvalidDate, "", "pass parseYearDayMonth")
 }
 
-func (p *Parser) parseYearDay() error {
+func (p *parseContext) parseYearDay() error {
 	if p.parsed.Year == "" {
-		return p.parseYear4()
+		return p.parseFullYear()
 	}
 	if p.parsed.Day == "" {
 		return p.parseOrdinalDay()
 	}
-	return p.err("pass parseYearDayMonth")
+	return p.err(ErrInvalidDate, "", "pass parseYearDayMonth")
 }
 
-func (p *Parser) parseDayMonthYear() error {
+func (p *parseContext) parseDayMonthYear() error {
 	if p.parsed.Day == "" {
 		return p.parseDay()
 	}
@@ -388,10 +616,10 @@ func (p *Parser) parseDayMonthYear() error {
 	if p.parsed.Year == "" {
 		return p.parseYear()
 	}
-	return p.err("pass parseDayMonth")
+	return p.err(ErrInvalidDate, "", "pass parseDayMonth")
 }
 
-func (p *Parser) parseMonthDayYear() error {
+func (p *parseContext) parseMonthDayYear() error {
 	if p.parsed.Month == "" {
 		return p.parseMonth()
 	}
@@ -401,75 +629,169 @@ func (p *Parser) parseMonthDayYear() error {
 	if p.parsed.Year == "" {
 		return p.parseYear()
 	}
-	return p.err("pass parseMonthDay")
+	return p.err(ErrInvalidDate, "", "pass parseMonthDay")
 }
 
-func (p *Parser) parseYear() error {
+// parseYear parses a year that may have two digits
+func (p *parseContext) parseYear() error {
 	p.trace("is year")
 	p.parsed.Year = p.tok.Val
-	switch len(p.parsed.Year) {
-	case 4:
-		//
-	case 2:
-		//
-	default:
-		return p.err("invalid year: %v", p.parsed.Year)
+	p.mark("Year", p.tok)
+	if len(p.parsed.Year) < 2 || len(p.parsed.Year) > 5 {
+		return p.err(ErrInvalidYear, "Year", "invalid year: %v", p.parsed.Year)
 	}
 	return nil
 }
 
-func (p *Parser) parseYear4() error {
-	p.trace("is year4")
+// parseFullYear parses a year that has three to five digits
+func (p *parseContext) parseFullYear() error {
+	p.trace("is full year")
+	if p.parsed.Year != "" {
+		return p.err(ErrInvalidYear, "Year", "unexpected year: %v", p.tok.Val)
+	}
 	p.parsed.Year = p.tok.Val
-	if len(p.parsed.Year) != 4 {
-		return p.err("invalid year: %v", p.parsed.Year)
+	p.mark("Year", p.tok)
+	if len(p.parsed.Year) < 3 || len(p.parsed.Year) > 5 {
+		return p.err(ErrInvalidYear, "Year", "invalid year: %v", p.parsed.Year)
+	}
+	return nil
+}
+
+// parseEraYear parses a year that comes before or after an era name. The
+// year can have any number of digits up to five, such as the "44" in
+// "44 BC".
+func (p *parseContext) parseEraYear() error {
+	p.trace("is era year")
+	if p.parsed.Year != "" {
+		return p.err(ErrInvalidYear, "Year", "unexpected year: %v", p.tok.Val)
+	}
+	p.parsed.Year = p.tok.Val
+	p.mark("Year", p.tok)
+	if n, _ := strconv.Atoi(p.tok.Val); n < 1 || len(p.tok.Val) > 5 {
+		return p.err(ErrInvalidYear, "Year", "invalid year: %v", p.tok.Val)
+	}
+	return nil
+}
+
+// parseEra parses an era name, such as "BC", that starts at the current
+// token and uses n tokens.
+func (p *parseContext) parseEra(era int, n int) error {
+	p.trace("is era")
+	if p.parsed.Era != "" {
+		return p.err(ErrInvalidYear, "Era", "unexpected era: %v", p.tok.Val)
+	}
+	p.parsed.Era = p.loc.EraNames.Main(era)
+	first := p.tok
+	for i := 1; i < n; i++ {
+		p.next()
 	}
+	p.mark("Era", p.span(first, p.tok))
 	return nil
 }
 
-func (p *Parser) parseMonth() error {
+// signedYear checks for a year that starts with a sign, such as "-0043",
+// at the start of a date.
+func (p *parseContext) signedYear() bool {
+	if (p.state != unknown && p.state != parsingDate) || hasDate(p.parsed) {
+		return false
+	}
+	if p.tok.Val != "-" && p.tok.Val != "+" {
+		return false
+	}
+	la := p.lookahead(1)
+	return la.Type == Number && len(la.Val) >= 4 && la.Pos == p.tok.Pos+1
+}
+
+// parseSignedYear parses a year with a sign. A negative year is an
+// astronomical year where "-0001" is 2 BC. The rest of the date is in
+// year-month-day order.
+func (p *parseContext) parseSignedYear() error {
+	sign := p.tok
+	p.next()
+	p.trace("is signed year")
+	if p.state == unknown {
+		p.changeState(parsingDate)
+	}
+	if p.dateOrder == unknownOrder && p.forced == unknownOrder {
+		p.dateOrder = yearMonthDayOrder
+		p.orderRule = ruleSeparator
+	}
+	if err := p.parseNumberDate(); err != nil {
+		return err
+	}
+	if p.parsed.Year != p.tok.Val {
+		return p.err(ErrInvalidYear, "Year", "unexpected signed year: %v%v", sign.Val, p.tok.Val)
+	}
+	if sign.Val == "-" {
+		p.parsed.Year = sign.Val + p.parsed.Year
+	}
+	p.mark("Year", p.span(sign, p.tok))
+	return nil
+}
+
+// lookaheadEra checks if the tokens starting at index i are an era name
+// and returns the era and the number of tokens used.
+func (p *parseContext) lookaheadEra(i int) (int, int) {
+	if i < 0 || i >= len(p.tokens) {
+		return 0, 0
+	}
+	era, n := 0, 0
+	for e, names := range p.loc.EraNames {
+		for _, name := range names {
+			if m := matchTokens(p.loc, p.tokens[i:], name); m > n {
+				era, n = e, m
+			}
+		}
+	}
+	return era, n
+}
+
+func (p *parseContext) parseMonth() error {
 	p.trace("is month")
 	p.parsed.Month = p.tok.Val
+	p.mark("Month", p.tok)
 	if _, ok := lookupMonth(p.loc, p.tok.Val); ok {
 		return nil
 	}
 	m, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid month: %v", p.tok.Val)
+		return p.err(ErrInvalidMonth, "Month", "invalid month: %v", p.tok.Val)
 	}
 	if m < 1 || m > 12 {
-		return p.err("invalid month: %v", p.tok.Val)
+		return p.err(ErrInvalidMonth, "Month", "invalid month: %v", p.tok.Val)
 	}
 	return nil
 }
 
-func (p *Parser) parseDay() error {
+func (p *parseContext) parseDay() error {
 	p.trace("is day")
 	p.parsed.Day = p.tok.Val
+	p.mark("Day", p.tok)
 	d, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid day: %v", p.tok.Val)
+		return p.err(ErrInvalidDay, "Day", "invalid day: %v", p.tok.Val)
 	}
 	if d < 1 || d > 31 {
-		return p.err("invalid day: %v", p.tok.Val)
+		return p.err(ErrInvalidDay, "Day", "invalid day: %v", p.tok.Val)
 	}
 	return nil
 }
 
-func (p *Parser) parseOrdinalDay() error {
+func (p *parseContext) parseOrdinalDay() error {
 	p.trace("is ordinal day")
 	p.parsed.Day = p.tok.Val
+	p.mark("Day", p.tok)
 	d, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid day: %v", p.tok.Val)
+		return p.err(ErrInvalidDay, "Day", "invalid day: %v", p.tok.Val)
 	}
-	if d < 1 || d > 365 {
-		return p.err("invalid day: %v", p.tok.Val)
+	if d < 1 || d > 366 {
+		return p.err(ErrInvalidDay, "Day", "invalid day: %v", p.tok.Val)
 	}
 	return nil
 }
 
-func (p *Parser) parseTime() error {
+func (p *parseContext) parseTime() error {
 	if p.parsed.Hour == "" {
 		return p.parseHour()
 	}
@@ -480,51 +802,55 @@ func (p *Parser) parseTime() error {
 		return p.parseSecond()
 	}
 	p.changeState(done)
-	return p.parseYear4()
+	return p.parseFullYear()
 }
 
-func (p *Parser) parseHour() error {
+func (p *parseContext) parseHour() error {
 	p.trace("is hour")
 	p.parsed.Hour = p.tok.Val
+	p.mark("Hour", p.tok)
 	h, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid hour: %v", p.tok.Val)
+		return p.err(ErrInvalidHour, "Hour", "invalid hour: %v", p.tok.Val)
 	}
 	if h < 0 || h >= 24 {
-		return p.err("invalid hour: %v", p.tok.Val)
+		return p.err(ErrInvalidHour, "Hour", "invalid hour: %v", p.tok.Val)
 	}
 
 	la := p.lookahead(1)
 	if inSet(la.Val, p.loc.HourSep) {
 		p.trace("HourSep = '%v'", la.Val)
 		p.parsed.HourSep = la.Val
+		p.mark("HourSep", la)
 		p.next()
 	}
 	return nil
 }
 
-func (p *Parser) parseMinute() error {
+func (p *parseContext) parseMinute() error {
 	p.trace("is minute")
 	p.parsed.Minute = p.tok.Val
+	p.mark("Minute", p.tok)
 	m, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid minute: %v", p.tok.Val)
+		return p.err(ErrInvalidMinute, "Minute", "invalid minute: %v", p.tok.Val)
 	}
 	if m < 0 || m >= 60 {
-		return p.err("invalid minute: %v", p.tok.Val)
+		return p.err(ErrInvalidMinute, "Minute", "invalid minute: %v", p.tok.Val)
 	}
 	return nil
 }
 
-func (p *Parser) parseSecond() error {
+func (p *parseContext) parseSecond() error {
 	p.trace("is second")
 	p.parsed.Second = p.tok.Val
+	p.mark("Second", p.tok)
 	s, err := strconv.Atoi(p.tok.Val)
 	if err != nil {
-		return p.err("invalid second: %v", p.tok.Val)
+		return p.err(ErrInvalidSecond, "Second", "invalid second: %v", p.tok.Val)
 	}
 	if s < 0 || s >= 60 {
-		return p.err("invalid second: %v", p.tok.Val)
+		return p.err(ErrInvalidSecond, "Second", "invalid second: %v", p.tok.Val)
 	}
 
 	la := p.lookahead(1)
@@ -533,19 +859,86 @@ func (p *Parser) parseSecond() error {
 		p.next()
 		p.next()
 		p.parsed.FracSecond = p.tok.Val
+		p.mark("FracSecond", p.tok)
 	}
 	return nil
 }
 
-func (p *Parser) parseOffset() error {
+// parseLocation parses a time zone name from the IANA database, such as
+// "America/New_York". Text that has a slash but is not a known name, such
+// as "Jan/Feb", is split at the slashes and parsed as usual.
+func (p *parseContext) parseLocation() (bool, error) {
+	if !strings.Contains(p.tok.Val, "/") {
+		return false, nil
+	}
+	var loc *time.Location
+	var err error
+	if isLocationName(p.tok.Val) {
+		loc, err = time.LoadLocation(p.tok.Val)
+	}
+	if loc == nil || err != nil {
+		p.trace("not a location")
+		p.splitPath()
+		return false, nil
+	}
+	p.trace("is location")
+	if p.parsed.Location != "" {
+		return true, p.err(ErrInvalidZone, "Location", "unexpected time zone: %v", p.tok.Val)
+	}
+	p.parsed.Location = loc.String()
+	p.mark("Location", p.tok)
+	if p.state != done {
+		p.changeState(parsingZone)
+	}
+	return true, nil
+}
+
+// isLocationName checks if the text looks like "Area/City". The area
+// starts with an uppercase letter and there is a letter after each slash.
+func isLocationName(v string) bool {
+	parts := strings.Split(v, "/")
+	if first, _ := utf8.DecodeRuneInString(parts[0]); !unicode.IsUpper(first) {
+		return false
+	}
+	for _, part := range parts[1:] {
+		if ch, _ := utf8.DecodeRuneInString(part); !unicode.IsLetter(ch) {
+			return false
+		}
+	}
+	return true
+}
+
+// splitPath replaces the current token with the tokens for the text
+// between the slashes and the slashes themselves.
+func (p *parseContext) splitPath() {
+	var split []Token
+	pos := p.tok.Pos
+	for i, part := range strings.Split(p.tok.Val, "/") {
+		if i > 0 {
+			split = append(split, Token{Indicator, "/", pos})
+			pos++
+		}
+		for _, tok := range scan(part) {
+			tok.Pos += pos - 1
+			split = append(split, tok)
+		}
+		pos += len(part)
+	}
+	rest := append(split, p.tokens[p.idx+1:]...)
+	p.tokens = append(p.tokens[:p.idx], rest...)
+	p.tok = p.tokens[p.idx]
+}
+
+func (p *parseContext) parseOffset() error {
 	p.trace("is offset")
+	first := p.tok
 	var parts []string
 	if p.tok.Type == Indicator && (p.tok.Val == "+" || p.tok.Val == "-") {
 		parts = append(parts, p.tok.Val)
 		p.next()
 	}
 	if p.tok.Type != Number {
-		return p.err("expecting offset but got '%v'", p.tok.Val)
+		return p.err(ErrInvalidOffset, "Offset", "expecting offset but got '%v'", p.tok.Val)
 	}
 	if len(p.tok.Val) == 4 {
 		parts = append(parts, p.tok.Val)
@@ -554,46 +947,126 @@ func (p *Parser) parseOffset() error {
 		parts = append(parts, p.tok.Val)
 		p.next()
 		if p.tok.Type != Indicator || p.tok.Val != ":" {
-			return p.err("expecting ':' in offset but got '%v'", p.tok.Val)
+			return p.err(ErrInvalidOffset, "Offset", "expecting ':' in offset but got '%v'", p.tok.Val)
 		}
 		p.next()
 		if p.tok.Type != Number {
-			return p.err("expecting offset minutes but got '%v'", p.tok.Val)
+			return p.err(ErrInvalidOffset, "Offset", "expecting offset minutes but got '%v'", p.tok.Val)
 		}
 		parts = append(parts, p.tok.Val)
 	}
 	offset := strings.Join(parts, "")
 	if p.parsed.Offset != "" && p.parsed.Offset != offset {
-		return p.err("offset mismatch between '%v' and '%v'", offset, p.parsed.Offset)
+		return p.err(ErrOffsetMismatch, "Offset", "offset mismatch between '%v' and '%v'", offset, p.parsed.Offset)
 	}
 	p.parsed.Offset = offset
+	p.mark("Offset", p.span(first, p.tok))
 	return nil
 }
 
-func (p *Parser) lookahead(n int) Token {
+// removeOrdinals removes ordinal suffixes, such as the "st" in "1st", that
+// directly follow a number and remembers the suffix by the position of that
+// number. A suffix that is used with the wrong number, such as "1th", is an
+// error.
+func (p *parseContext) removeOrdinals(tokens []Token) ([]Token, error) {
+	var result []Token
+	for i, tok := range tokens {
+		if i == 0 || !isAttached(tokens[i-1], tok) {
+			result = append(result, tok)
+			continue
+		}
+		num := tokens[i-1]
+		n, err := strconv.Atoi(num.Val)
+		if err != nil || len(num.Val) > 2 || n < 1 || n > 31 || !p.isSuffix(tok.Val) {
+			result = append(result, tok)
+			continue
+		}
+		if suffix, _ := p.loc.OrdinalSuffix(n); p.loc.Key(tok.Val) != p.loc.Key(suffix) {
+			p.tok = tok
+			return nil, p.err(ErrInvalidDay, "Day", "invalid ordinal: %v%v", num.Val, tok.Val)
+		}
+		p.trace("ordinal suffix: %v", tok.Val)
+		if p.ordinals == nil {
+			p.ordinals = make(map[int]Token)
+		}
+		p.ordinals[num.Pos] = tok
+	}
+	return result, nil
+}
+
+// isAttached checks if the text directly follows the number without
+// anything in between.
+func isAttached(num Token, text Token) bool {
+	return num.Type == Number && text.Type == Text && text.Pos == num.Pos+len(num.Val)
+}
+
+func (p *parseContext) isSuffix(text string) bool {
+	for _, suffix := range p.loc.OrdinalSuffixes {
+		if p.loc.Key(text) == p.loc.Key(suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parseContext) lookahead(n int) Token {
 	if n+p.idx >= len(p.tokens) {
-		return Token{End, "", 0}
+		return Token{End, "", p.end}
 	}
 	return p.tokens[n+p.idx]
 }
 
-func (p *Parser) next() {
+func (p *parseContext) next() {
 	p.idx++
 	if p.idx >= len(p.tokens) {
 		p.trace("end")
 		p.idx = len(p.tokens)
-		p.tok = Token{End, "", 0}
+		p.tok = Token{End, "", p.end}
 		return
 	}
 	p.tok = p.tokens[p.idx]
 	p.trace("next: %v", p.tok)
 }
 
-func (p *Parser) err(format string, a ...any) error {
-	return fmt.Errorf(format, a...)
+// mark records the token that a field came from when the details of the
+// parse were requested.
+func (p *parseContext) mark(field string, tok Token) {
+	if p.fields != nil {
+		p.fields[field] = p.source(tok)
+	}
+}
+
+// discard records the current token as one that was ignored when the
+// details of the parse were requested.
+func (p *parseContext) discard() {
+	if p.fields != nil {
+		p.discarded = append(p.discarded, p.source(p.tok))
+	}
+}
+
+// span returns a token for the text from the start of the first token to
+// the end of the last token. It has the type of the first token.
+func (p *parseContext) span(first Token, last Token) Token {
+	return Token{first.Type, p.text[first.Pos-1 : last.Pos-1+len(last.Val)], first.Pos}
+}
+
+// source returns the token with the value and position from the text that
+// was given to the parser.
+func (p *parseContext) source(tok Token) Token {
+	return sourceToken(p.src, p.offsets, tok)
+}
+
+func (p *parseContext) err(code ErrorCode, field string, format string, a ...any) error {
+	return &ParseError{
+		Code:  code,
+		Field: field,
+		Token: p.tok,
+		State: p.state.String(),
+		Msg:   fmt.Sprintf(format, a...),
+	}
 }
 
-func (p *Parser) trace(format string, a ...any) {
+func (p *parseContext) trace(format string, a ...any) {
 	if p.Trace {
 		fmt.Printf(format, a...)
 		fmt.Println()
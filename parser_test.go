@@ -1,6 +1,7 @@
 package ptime
 
 import (
+	"errors"
 	"strings"
 	"testing"
 
@@ -29,6 +30,11 @@ func TestParserEnUS(t *testing.T) {
 			Day:     "002",
 			DateSep: "-",
 		}},
+		{"date", "2024-366", Parsed{
+			Year:    "2024",
+			Day:     "366",
+			DateSep: "-",
+		}},
 		{"date", "1/2", Parsed{
 			Month:   "1",
 			Day:     "2",
@@ -199,6 +205,24 @@ func TestParserEnUS(t *testing.T) {
 			Offset:  "+0000",
 			TimeSep: ":",
 		}},
+		{"time", "3:04pm America/New_York", Parsed{
+			Hour:     "3",
+			Minute:   "04",
+			Period:   "PM",
+			Location: "America/New_York",
+			TimeSep:  ":",
+		}},
+		{"time", "3pm Mars/Olympus_Mons", Parsed{
+			Hour:   "3",
+			Period: "PM",
+		}},
+		{"time", "15:04 -05:00 America/Port-au-Prince", Parsed{
+			Hour:     "15",
+			Minute:   "04",
+			Offset:   "-0500",
+			Location: "America/Port-au-Prince",
+			TimeSep:  ":",
+		}},
 
 		{"parse", "Mon Jan 2 2006 15:04:05 MST", Parsed{
 			Weekday: "Mon",
@@ -266,6 +290,13 @@ func TestParserEnUS(t *testing.T) {
 			TimeSep: ":",
 		}},
 		// RFC822
+		{"time", "15:04 mst", Parsed{
+			Hour:    "15",
+			Minute:  "04",
+			Zone:    "MST",
+			Offset:  "-0700",
+			TimeSep: ":",
+		}},
 		{"parse", "02 Jan 06 15:04 MST", Parsed{
 			Day:     "02",
 			Month:   "Jan",
@@ -371,6 +402,163 @@ func TestParserEnUS(t *testing.T) {
 			TimeSep:     ":",
 			DateTimeSep: "T",
 		}},
+
+		{"parse", "3pm", Parsed{
+			Hour:   "3",
+			Period: "PM",
+		}},
+		{"parse", "tomorrow", Parsed{
+			Relative:     "+1",
+			RelativeUnit: "day",
+		}},
+		{"parse", "yesterday", Parsed{
+			Relative:     "-1",
+			RelativeUnit: "day",
+		}},
+		{"parse", "tomorrow 3pm", Parsed{
+			Hour:         "3",
+			Period:       "PM",
+			Relative:     "+1",
+			RelativeUnit: "day",
+		}},
+		{"parse", "3pm tomorrow", Parsed{
+			Hour:         "3",
+			Period:       "PM",
+			Relative:     "+1",
+			RelativeUnit: "day",
+		}},
+		{"parse", "next Friday", Parsed{
+			Weekday:  "Fri",
+			Relative: "+1",
+		}},
+		{"parse", "next Friday 5pm", Parsed{
+			Weekday:  "Fri",
+			Hour:     "5",
+			Period:   "PM",
+			Relative: "+1",
+		}},
+		{"parse", "noon", Parsed{
+			Period: "noon",
+		}},
+		{"parse", "noon tomorrow", Parsed{
+			Period:       "noon",
+			Relative:     "+1",
+			RelativeUnit: "day",
+		}},
+		{"parse", "Jan 3 midnight", Parsed{
+			Month:   "Jan",
+			Day:     "3",
+			Period:  "midnight",
+			DateSep: " ",
+		}},
+		{"parse", "12 noon", Parsed{
+			Hour:   "12",
+			Period: "noon",
+		}},
+		{"parse", "last Monday", Parsed{
+			Weekday:  "Mon",
+			Relative: "-1",
+		}},
+		{"parse", "next month", Parsed{
+			Relative:     "+1",
+			RelativeUnit: "month",
+		}},
+		{"parse", "in 3 days", Parsed{
+			Relative:     "+3",
+			RelativeUnit: "day",
+		}},
+		{"parse", "2 weeks ago", Parsed{
+			Relative:     "-2",
+			RelativeUnit: "week",
+		}},
+
+		{"date", "March 1st, 2023", Parsed{
+			Month:   "Mar",
+			Day:     "1",
+			Year:    "2023",
+			DateSep: " ",
+		}},
+		{"date", "22nd Jan", Parsed{
+			Month:   "Jan",
+			Day:     "22",
+			DateSep: " ",
+		}},
+		{"date", "Fri 13TH", Parsed{
+			Weekday: "Fri",
+			Day:     "13",
+			DateSep: " ",
+		}},
+
+		{"date", "44 BC", Parsed{
+			Year: "44",
+			Era:  "BC",
+		}},
+		{"date", "15 March 44 B.C.", Parsed{
+			Day:     "15",
+			Month:   "Mar",
+			Year:    "44",
+			Era:     "BC",
+			DateSep: " ",
+		}},
+		{"date", "AD 1066", Parsed{
+			Year: "1066",
+			Era:  "AD",
+		}},
+		{"date", "Oct 14 1066 CE", Parsed{
+			Month:   "Oct",
+			Day:     "14",
+			Year:    "1066",
+			Era:     "AD",
+			DateSep: " ",
+		}},
+		{"date", "Jan 2 999", Parsed{
+			Month:   "Jan",
+			Day:     "2",
+			Year:    "999",
+			DateSep: " ",
+		}},
+		{"date", "10000-01-02", Parsed{
+			Year:    "10000",
+			Month:   "01",
+			Day:     "02",
+			DateSep: "-",
+		}},
+		{"date", "-0043-03-15", Parsed{
+			Year:    "-0043",
+			Month:   "03",
+			Day:     "15",
+			DateSep: "-",
+		}},
+		{"date", "٢٠٠٦-٠١-٠٢", Parsed{
+			Year:    "2006",
+			Month:   "01",
+			Day:     "02",
+			DateSep: "-",
+		}},
+		{"date", "२००६-०१-०२", Parsed{
+			Year:    "2006",
+			Month:   "01",
+			Day:     "02",
+			DateSep: "-",
+		}},
+		{"date", "۱/۲/۲۰۰۶", Parsed{
+			Month:   "1",
+			Day:     "2",
+			Year:    "2006",
+			DateSep: "/",
+		}},
+		{"date", "１／２／２００６", Parsed{
+			Month:   "1",
+			Day:     "2",
+			Year:    "2006",
+			DateSep: "/",
+		}},
+		{"time", "３：０４ ＰＭ", Parsed{
+			Hour:    "3",
+			Minute:  "04",
+			Period:  "PM",
+			TimeSep: ":",
+		}},
 	}
 
 	p := NewParser(locale.EnUS)
@@ -414,6 +602,36 @@ func TestParserFrFR(t *testing.T) {
 			Day:     "2",
 			DateSep: " ",
 		}},
+		{"date", "1er mars 2023", Parsed{
+			Month:   "mars",
+			Day:     "1",
+			Year:    "2023",
+			DateSep: " ",
+		}},
+		{"date", "3e mars", Parsed{
+			Month:   "mars",
+			Day:     "3",
+			DateSep: " ",
+		}},
+		{"date", "le 1er mars", Parsed{
+			Month:   "mars",
+			Day:     "1",
+			DateSep: " ",
+		}},
+		{"date", "lundi le 2 janvier 2006", Parsed{
+			Weekday: "lun.",
+			Month:   "janv.",
+			Day:     "2",
+			Year:    "2006",
+			DateSep: " ",
+		}},
+		{"date", "15 mars 44 av. J.-C.", Parsed{
+			Day:     "15",
+			Month:   "mars",
+			Year:    "44",
+			Era:     "av. J.-C.",
+			DateSep: " ",
+		}},
 
 		{"time", "15:04:05,9999", Parsed{
 			Hour:       "15",
@@ -445,6 +663,62 @@ func TestParserFrFR(t *testing.T) {
 			DateSep:    "/",
 			TimeSep:    ":",
 		}},
+
+		{"parse", "demain", Parsed{
+			Relative:     "+1",
+			RelativeUnit: "jour",
+		}},
+		{"parse", "hier", Parsed{
+			Relative:     "-1",
+			RelativeUnit: "jour",
+		}},
+		{"parse", "après-demain 15h", Parsed{
+			Hour:         "15",
+			HourSep:      "h",
+			Relative:     "+2",
+			RelativeUnit: "jour",
+		}},
+		{"parse", "vendredi prochain", Parsed{
+			Weekday:  "ven.",
+			Relative: "+1",
+		}},
+		{"parse", "vendredi prochain 17h", Parsed{
+			Weekday:  "ven.",
+			Hour:     "17",
+			HourSep:  "h",
+			Relative: "+1",
+		}},
+		{"parse", "dans 3 jours", Parsed{
+			Relative:     "+3",
+			RelativeUnit: "jour",
+		}},
+		{"parse", "il y a 2 semaines", Parsed{
+			Relative:     "-2",
+			RelativeUnit: "semaine",
+		}},
+		{"date", "2 fe\u0301vrier 2006", Parsed{
+			Month:   "févr.",
+			Day:     "2",
+			Year:    "2006",
+			DateSep: " ",
+		}},
+		{"date", "2 FÉVR. 2006", Parsed{
+			Month:   "févr.",
+			Day:     "2",
+			Year:    "2006",
+			DateSep: " ",
+		}},
+		{"date", "15\u00a0mars 44 av.\u00a0J.\u2011C.", Parsed{
+			Month:   "mars",
+			Day:     "15",
+			Year:    "44",
+			Era:     "av. J.-C.",
+			DateSep: " ",
+		}},
+		{"parse", "aujourd\u2019hui", Parsed{
+			Relative:     "0",
+			RelativeUnit: "jour",
+		}},
 	}
 
 	p := NewParser(locale.FrFR)
@@ -455,6 +729,31 @@ func TestParserFrFR(t *testing.T) {
 	}
 }
 
+func TestParserFoldDiacritics(t *testing.T) {
+	tests := []struct {
+		text   string
+		parsed Parsed
+	}{
+		{"2 fevrier 2006", Parsed{Month: "févr.", Day: "2", Year: "2006", DateSep: " "}},
+		{"2 FEVR. 2006", Parsed{Month: "févr.", Day: "2", Year: "2006", DateSep: " "}},
+		{"15 aout", Parsed{Month: "août", Day: "15", DateSep: " "}},
+		{"apres-demain", Parsed{Relative: "+2", RelativeUnit: "jour"}},
+	}
+
+	def := locale.FrFR.Def
+	def.FoldDiacritics = true
+	p := NewParser(locale.MustNew(def))
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			testValid(t, p, "parse", test.text, test.parsed)
+		})
+	}
+
+	if _, err := NewParser(locale.FrFR).Parse("2 fevrier 2006"); err == nil {
+		t.Errorf("expected error without folding")
+	}
+}
+
 func TestParserErrorEnUS(t *testing.T) {
 	tests := []struct {
 		fn   string
@@ -466,6 +765,19 @@ func TestParserErrorEnUS(t *testing.T) {
 
 		{"time", "3:04am +1000 EST", "does not match given offset"},
 		{"time", "2006-01-02", "invalid hour"},
+
+		{"parse", "3 days", "missing direction"},
+		{"parse", "next", "incomplete relative date"},
+		{"parse", "next last Friday", "unexpected relative text"},
+		{"parse", "March 1th", "invalid ordinal: 1th"},
+		{"parse", "3:04 1st", "unexpected ordinal day"},
+		{"parse", "BC", "missing year for era"},
+		{"parse", "0 BC", "invalid year: 0"},
+		{"parse", "Jan 2 123456", "invalid year"},
+		{"parse", "Jan 2 2006 12:00:00 123", "unexpected year: 123"},
+		{"parse", "15pm", "invalid hour for pm: 15"},
+		{"parse", "0:30am", "invalid hour for am: 0"},
+		{"parse", "3 noon", "invalid hour for noon: 3"},
 	}
 
 	p := NewParser(locale.EnUS)
@@ -487,6 +799,50 @@ func TestParserErrorEnUS(t *testing.T) {
 				parsed, err := p.ParseTime(test.text)
 				check(parsed, err)
 			}
+			if test.fn == "parse" {
+				parsed, err := p.Parse(test.text)
+				check(parsed, err)
+			}
+		})
+	}
+}
+
+func TestParseError(t *testing.T) {
+	tests := []struct {
+		text  string
+		code  ErrorCode
+		field string
+		tok   Token
+	}{
+		{"2006-13-01", ErrInvalidMonth, "Month", Token{Number, "13", 6}},
+		{"Jan 32", ErrInvalidDay, "Day", Token{Number, "32", 5}},
+		{"Jan 2st", ErrInvalidDay, "Day", Token{Text, "st", 6}},
+		{"3:04am +1000 EST", ErrZoneMismatch, "Zone", Token{Text, "EST", 14}},
+		{"3:04 +", ErrInvalidOffset, "Offset", Token{End, "", 7}},
+		{"3 days", ErrInvalidRelative, "Relative", Token{End, "", 7}},
+		{"13:00 pm", ErrInvalidHour, "Hour", Token{Text, "pm", 7}},
+		{"Jan/Feb", ErrUnexpectedText, "", Token{Text, "Feb", 5}},
+		{"٢٠٠٦-١٣-٠١", ErrInvalidMonth, "Month", Token{Number, "١٣", 10}},
+		{"３:04 ＋", ErrInvalidOffset, "Offset", Token{End, "", 11}},
+	}
+
+	p := NewParser(locale.EnUS)
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			_, err := p.Parse(test.text)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected ParseError, have: %v", err)
+			}
+			if perr.Code != test.code {
+				t.Errorf("\n have code: %v \n want code: %v", perr.Code, test.code)
+			}
+			if perr.Field != test.field {
+				t.Errorf("\n have field: %v \n want field: %v", perr.Field, test.field)
+			}
+			if perr.Token != test.tok {
+				t.Errorf("\n have token: %v \n want token: %v", perr.Token, test.tok)
+			}
 		})
 	}
 }
@@ -494,7 +850,7 @@ func TestParserErrorEnUS(t *testing.T) {
 func testValid(t *testing.T, p *Parser, fn string, text string, want Parsed) {
 	check := func(have Parsed, want Parsed, err error) {
 		if err != nil {
-			t.Fatalf("unexpected error: %v \n have: %v \n tokens: %v", err, have, p.tokens)
+			t.Fatalf("unexpected error: %v \n have: %v \n tokens: %v", err, have, Scan(text))
 		}
 		if have != want {
 			t.Errorf("\n have: %v \n want: %v", have, want)
@@ -19,8 +19,11 @@ func For(loc *locale.Locale) *P {
 	}
 }
 
+// ForLocale creates a P for the registered locale that best matches the
+// BCP 47 language tag in name. See locale.LookupTag for the fallbacks that
+// are used.
 func ForLocale(name string) (*P, error) {
-	loc, ok := locale.Lookup(name)
+	loc, ok := locale.LookupTag(name)
 	if !ok {
 		return nil, fmt.Errorf("unknown locale: %v", name)
 	}
@@ -39,23 +42,54 @@ func (p *P) ParseTime(text string) (Parsed, error) {
 	return p.Parser.ParseTime(text)
 }
 
+func (p *P) ParseDetail(text string) (Detail, error) {
+	return p.Parser.ParseDetail(text)
+}
+
+func (p *P) ParseAll(text string) ([]Candidate, error) {
+	return p.Parser.ParseAll(text)
+}
+
+func (p *P) ParseInterval(text string) (Interval, error) {
+	return p.Parser.ParseInterval(text)
+}
+
+func (p *P) ParseRange(text string) (Parsed, Parsed, error) {
+	return p.Parser.ParseRange(text)
+}
+
+func (p *P) ParseStrptime(layout string, text string) (Parsed, error) {
+	return ParseStrptime(p.Locale, layout, text)
+}
+
 func (p *P) Time(parsed Parsed, now time.Time) (time.Time, error) {
-	return Time(p.Locale, parsed, now)
+	return p.Parser.Time(parsed, now)
+}
+
+func (p *P) IntervalTime(iv Interval, now time.Time) (time.Time, time.Time, error) {
+	return p.Parser.IntervalTime(iv, now)
+}
+
+func (p *P) TimeRange(start Parsed, end Parsed, now time.Time) (time.Time, time.Time, error) {
+	return p.Parser.TimeRange(start, end, now)
 }
 
 func (p *P) Format(layout string, t time.Time) string {
 	return Format(p.Locale, layout, t)
 }
 
+func (p *P) FormatStrftime(layout string, t time.Time) string {
+	return FormatStrftime(p.Locale, layout, t)
+}
+
+func (p *P) CompileFormat(layout string) (*Layout, error) {
+	return CompileFormat(p.Locale, layout)
+}
+
+func (p *P) FormatGo(goLayout string, t time.Time) string {
+	return FormatGo(p.Locale, goLayout, t)
+}
+
 func FormatOffset(offset int, sep string) string {
-	sign := "+"
-	if offset < 0 {
-		sign = "-"
-	}
-	h := offset / 3600
-	if h < 0 {
-		h = h * -1
-	}
-	m := offset / 60 % 60
-	return fmt.Sprintf("%v%02d%v%02d", sign, h, sep, m)
+	return string(appendOffset(nil, offset, sep))
 }
@@ -0,0 +1,305 @@
+package ptime
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// rangeSplit is a place where the text can be split into a start and an
+// end. Separators surrounded by spaces are tried first since a "-" could
+// also be a date separator.
+type rangeSplit struct {
+	start  string
+	end    string
+	spaced bool
+}
+
+// ParseRange parses text that contains a start and an end separated by one
+// of the range separators in the locale, such as "Jan 3 - Jan 7" or
+// "9am to 5pm". Fields that are missing from one side are taken from the
+// other so that "Jan 3-7" ends on Jan 7.
+func (p *Parser) ParseRange(text string) (Parsed, Parsed, error) {
+	noSep := &ParseError{
+		Code:  ErrInvalidRange,
+		Token: Token{End, "", len(text) + 1},
+		State: unknown.String(),
+		Msg:   "no range separator found",
+	}
+	splits := p.rangeSplits(normalizeText(text))
+	if len(splits) == 0 {
+		return Parsed{}, Parsed{}, noSep
+	}
+
+	var firstErr error
+	for _, split := range splits {
+		start, end, err := p.parseRangeSplit(split)
+		if err == nil {
+			inheritRange(p.loc, &start, &end)
+			return start, end, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	// A separator without spaces, such as the "-" in "2006-01-02", may
+	// only be part of a date so the error from that side is not useful.
+	if !splits[0].spaced {
+		return Parsed{}, Parsed{}, noSep
+	}
+	return Parsed{}, Parsed{}, firstErr
+}
+
+func (p *Parser) rangeSplits(text string) []rangeSplit {
+	tokens := scan(text)
+	var spaced, unspaced []rangeSplit
+	for i, tok := range tokens {
+		n := 0
+		for _, sep := range p.loc.RangeSep {
+			if m := matchTokens(p.loc, tokens[i:], sep); m > n {
+				n = m
+			}
+		}
+		if n == 0 {
+			continue
+		}
+		last := tokens[i+n-1]
+		from := tok.Pos - 1
+		to := last.Pos - 1 + len(last.Val)
+		split := rangeSplit{
+			start:  strings.TrimSpace(text[:from]),
+			end:    strings.TrimSpace(text[to:]),
+			spaced: from > 0 && to < len(text) && text[from-1] == ' ' && text[to] == ' ',
+		}
+		if split.start == "" || split.end == "" {
+			continue
+		}
+		if split.spaced {
+			spaced = append(spaced, split)
+		} else {
+			unspaced = append(unspaced, split)
+		}
+	}
+	return append(spaced, unspaced...)
+}
+
+func (p *Parser) parseRangeSplit(split rangeSplit) (Parsed, Parsed, error) {
+	startText := split.start
+	tokens := scan(startText)
+	for _, name := range p.loc.RangeStartNames {
+		if n := matchTokens(p.loc, tokens, name); n > 0 && n < len(tokens) {
+			startText = strings.TrimSpace(startText[tokens[n].Pos-1:])
+			break
+		}
+	}
+
+	var start, end Parsed
+	var err error
+	startBare := isBareNumber(startText)
+	endBare := isBareNumber(split.end)
+	if !startBare {
+		if start, err = p.parseRangeSide(startText); err != nil {
+			return start, end, err
+		}
+	}
+	if !endBare {
+		if end, err = p.parseRangeSide(split.end); err != nil {
+			return start, end, err
+		}
+	}
+	if startBare {
+		if start, err = bareRange(startText, end); err != nil {
+			return start, end, err
+		}
+	}
+	if endBare {
+		if end, err = bareRange(split.end, start); err != nil {
+			return start, end, err
+		}
+	}
+	return start, end, nil
+}
+
+// parseRangeSide parses one side of a range. Times like "9h" are only
+// recognized when the parser knows to expect a time so that is tried if
+// the text cannot be parsed otherwise.
+func (p *Parser) parseRangeSide(text string) (Parsed, error) {
+	parsed, err := p.Parse(text)
+	if err != nil {
+		if timeParsed, timeErr := p.ParseTime(text); timeErr == nil {
+			return timeParsed, nil
+		}
+	}
+	return parsed, err
+}
+
+// matchTokens returns the number of tokens at the start of the list that
+// match the phrase or zero if there is no match.
+func matchTokens(l *locale.Locale, tokens []Token, phrase string) int {
+	want := scan(normalizeText(phrase))
+	if len(want) == 0 || len(want) > len(tokens) {
+		return 0
+	}
+	for i, w := range want {
+		have := tokens[i]
+		if have.Type != w.Type || l.Key(have.Val) != l.Key(w.Val) {
+			return 0
+		}
+	}
+	return len(want)
+}
+
+func isBareNumber(text string) bool {
+	tokens := scan(text)
+	return len(tokens) == 1 && tokens[0].Type == Number
+}
+
+// bareRange uses the other side of the range to figure out which field a
+// number given by itself belongs to. In "9-11am" it is the hour and in
+// "Jan 3-7" it is the day.
+func bareRange(text string, other Parsed) (Parsed, error) {
+	switch {
+	case other.Hour != "":
+		return Parsed{Hour: text}, nil
+	case len(text) == 4:
+		return Parsed{Year: text}, nil
+	case other.Day != "":
+		return Parsed{Day: text}, nil
+	}
+	return Parsed{}, &ParseError{
+		Code:  ErrInvalidRange,
+		Token: Token{Number, text, 1},
+		State: unknown.String(),
+		Msg:   fmt.Sprintf("unable to determine field for number: %v", text),
+	}
+}
+
+// inheritRange copies the fields that are missing on one side of a range
+// from the other side.
+func inheritRange(l *locale.Locale, start *Parsed, end *Parsed) {
+	// A side with only a time is on the same day as the other side
+	if !hasDate(*end) && end.Weekday == "" {
+		copyDate(end, *start)
+	}
+	if !hasDate(*start) && start.Weekday == "" {
+		copyDate(start, *end)
+	}
+	if end.Day != "" && end.Month == "" && start.Month != "" {
+		end.Month = start.Month
+	}
+	if start.Day != "" && start.Month == "" && end.Month != "" {
+		start.Month = end.Month
+	}
+
+	// If the months wrap around the end of the year, so does the year
+	startMon, startOk := monthNum(l, start.Month)
+	endMon, endOk := monthNum(l, end.Month)
+	wrap := startOk && endOk && endMon < startMon
+	if end.Year == "" && start.Year != "" && end.Month != "" {
+		end.Year = addYear(start.Year, wrap, 1)
+	}
+	if start.Year == "" && end.Year != "" && start.Month != "" {
+		start.Year = addYear(end.Year, wrap, -1)
+	}
+
+	if start.Zone == "" && start.Offset == "" && start.Location == "" {
+		start.Zone, start.Offset, start.Location = end.Zone, end.Offset, end.Location
+	}
+	if end.Zone == "" && end.Offset == "" && end.Location == "" {
+		end.Zone, end.Offset, end.Location = start.Zone, start.Offset, start.Location
+	}
+
+	// Only use the same period if the hours are in order on a 12-hour
+	// clock. In "9-11am" both are in the morning but in "11-1pm" and
+	// "11-12pm" the start is not.
+	startHour, _ := strconv.Atoi(start.Hour)
+	endHour, _ := strconv.Atoi(end.Hour)
+	if start.Hour != "" && end.Hour != "" && startHour%12 <= endHour%12 {
+		if start.Period == "" {
+			start.Period = end.Period
+		}
+		if end.Period == "" {
+			end.Period = start.Period
+		}
+	}
+}
+
+// weekdayAfter moves the time to the first day with the weekday on or after
+// the start. The time of day is kept.
+func weekdayAfter(weekday int, start time.Time, t time.Time) time.Time {
+	ahead := (weekday - int(start.Weekday()) + 7) % 7
+	y, m, d := start.AddDate(0, 0, ahead).Date()
+	return time.Date(y, m, d, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+}
+
+func copyDate(to *Parsed, from Parsed) {
+	to.Weekday = from.Weekday
+	to.Year = from.Year
+	to.Month = from.Month
+	to.Day = from.Day
+	to.Week = from.Week
+	to.DateSep = from.DateSep
+	to.Relative = from.Relative
+	to.RelativeUnit = from.RelativeUnit
+}
+
+func hasDate(p Parsed) bool {
+	return p.Year != "" || p.Month != "" || p.Day != "" || p.Week != "" || p.Relative != ""
+}
+
+func monthNum(l *locale.Locale, month string) (int, bool) {
+	if n, ok := l.MonthNum[l.Key(month)]; ok {
+		return n, true
+	}
+	n, err := strconv.Atoi(month)
+	return n, err == nil
+}
+
+func addYear(year string, wrap bool, n int) string {
+	if !wrap {
+		return year
+	}
+	y, err := strconv.Atoi(year)
+	if err != nil {
+		return year
+	}
+	return fmt.Sprintf("%0*d", len(year), y+n)
+}
+
+// TimeRange returns the start and end times for a range. The start is
+// resolved with the Resolve policy of the parser and an end that is only a
+// weekday, as in "Mon - Fri", is the first day on or after the start with
+// that weekday. If the end would come before the start, it is moved to the
+// next day when both are on the same date or to the next year when the end
+// does not have a year.
+func TimeRange(l *locale.Locale, start Parsed, end Parsed, now time.Time) (time.Time, time.Time, error) {
+	return NewParser(l).TimeRange(start, end, now)
+}
+
+// TimeRange returns the start and end times for a range using the options
+// of the parser.
+func (p *Parser) TimeRange(start Parsed, end Parsed, now time.Time) (time.Time, time.Time, error) {
+	s, err := p.Time(start, now)
+	if err != nil {
+		return s, time.Time{}, err
+	}
+	e, err := p.Time(end, now)
+	if err != nil {
+		return s, e, err
+	}
+	if end.Weekday != "" && !hasDate(end) {
+		e = weekdayAfter(p.loc.DayNum[p.loc.Key(end.Weekday)], s, e)
+	}
+	if e.Before(s) {
+		switch {
+		case start.Year == end.Year && start.Month == end.Month && start.Day == end.Day && start.Week == end.Week:
+			e = e.AddDate(0, 0, 1)
+		case end.Year == "":
+			e = e.AddDate(1, 0, 0)
+		}
+	}
+	return s, e, nil
+}
@@ -0,0 +1,158 @@
+package ptime
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestParseRange(t *testing.T) {
+	tests := []struct {
+		loc   *locale.Locale
+		text  string
+		start Parsed
+		end   Parsed
+	}{
+		{locale.EnUS, "Jan 3 - Jan 7",
+			Parsed{Month: "Jan", Day: "3", DateSep: " "},
+			Parsed{Month: "Jan", Day: "7", DateSep: " "}},
+		{locale.EnUS, "Jan 3-7",
+			Parsed{Month: "Jan", Day: "3", DateSep: " "},
+			Parsed{Month: "Jan", Day: "7"}},
+		{locale.EnUS, "9am to 5pm",
+			Parsed{Hour: "9", Period: "AM"},
+			Parsed{Hour: "5", Period: "PM"}},
+		{locale.EnUS, "9-11am",
+			Parsed{Hour: "9", Period: "AM"},
+			Parsed{Hour: "11", Period: "AM"}},
+		{locale.EnUS, "11-1pm",
+			Parsed{Hour: "11"},
+			Parsed{Hour: "1", Period: "PM"}},
+		{locale.EnUS, "from 10:00 until 11:30 EST",
+			Parsed{Hour: "10", Minute: "00", TimeSep: ":", Zone: "EST", Offset: "-0500"},
+			Parsed{Hour: "11", Minute: "30", TimeSep: ":", Zone: "EST", Offset: "-0500"}},
+		{locale.EnUS, "2006-01-02 - 2006-01-05",
+			Parsed{Year: "2006", Month: "01", Day: "02", DateSep: "-"},
+			Parsed{Year: "2006", Month: "01", Day: "05", DateSep: "-"}},
+		{locale.EnUS, "Dec 30 2006 through Jan 2",
+			Parsed{Month: "Dec", Day: "30", Year: "2006", DateSep: " "},
+			Parsed{Month: "Jan", Day: "2", Year: "2007", DateSep: " "}},
+		{locale.EnUS, "Dec 30 - Jan 2 2007",
+			Parsed{Month: "Dec", Day: "30", Year: "2006", DateSep: " "},
+			Parsed{Month: "Jan", Day: "2", Year: "2007", DateSep: " "}},
+		{locale.EnUS, "Jan 3 9:00am - 5pm",
+			Parsed{Month: "Jan", Day: "3", Hour: "9", Minute: "00", Period: "AM", DateSep: " ", TimeSep: ":"},
+			Parsed{Month: "Jan", Day: "3", Hour: "5", Period: "PM", DateSep: " "}},
+		{locale.EnUS, "2006-2008",
+			Parsed{Year: "2006"},
+			Parsed{Year: "2008"}},
+		{locale.FrFR, "du 3 au 7 janvier",
+			Parsed{Month: "janv.", Day: "3"},
+			Parsed{Month: "janv.", Day: "7", DateSep: " "}},
+		{locale.FrFR, "de 9h à 17h",
+			Parsed{Hour: "9", HourSep: "h"},
+			Parsed{Hour: "17", HourSep: "h"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			p := NewParser(test.loc)
+			start, end, err := p.ParseRange(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(start, test.start) {
+				t.Errorf("start\n have: %v \n want: %v", start, test.start)
+			}
+			if !reflect.DeepEqual(end, test.end) {
+				t.Errorf("end\n have: %v \n want: %v", end, test.end)
+			}
+		})
+	}
+}
+
+func TestParseRangeError(t *testing.T) {
+	tests := []struct {
+		text string
+		code ErrorCode
+	}{
+		{"Jan 3", ErrInvalidRange},
+		{"3 - 7", ErrInvalidRange},
+		{"Jan 3 - Foo", ErrUnexpectedText},
+		{"2006-01-02", ErrInvalidRange},
+	}
+
+	p := NewParser(locale.EnUS)
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			_, _, err := p.ParseRange(test.text)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected parse error, have: %v", err)
+			}
+			if perr.Code != test.code {
+				t.Errorf("\n have: %v \n want: %v", perr.Code, test.code)
+			}
+		})
+	}
+}
+
+func TestTimeRange(t *testing.T) {
+	now := time.Date(2006, 01, 02, 15, 04, 05, 0, time.UTC)
+	tests := []struct {
+		text  string
+		start time.Time
+		end   time.Time
+	}{
+		{"Jan 3-7",
+			time.Date(2006, 1, 3, 0, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 7, 0, 0, 0, 0, time.UTC)},
+		{"9am to 5pm",
+			time.Date(2006, 1, 2, 9, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 2, 17, 0, 0, 0, time.UTC)},
+		{"11-12pm",
+			time.Date(2006, 1, 2, 11, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 2, 12, 0, 0, 0, time.UTC)},
+		{"12-1pm",
+			time.Date(2006, 1, 2, 12, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 2, 13, 0, 0, 0, time.UTC)},
+		{"10pm - 2am",
+			time.Date(2006, 1, 2, 22, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 3, 2, 0, 0, 0, time.UTC)},
+		{"Dec 30 - Jan 2",
+			time.Date(2006, 12, 30, 0, 0, 0, 0, time.UTC),
+			time.Date(2007, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{"Jan 3 10:00pm - 2am",
+			time.Date(2006, 1, 3, 22, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 4, 2, 0, 0, 0, time.UTC)},
+		{"Mon - Fri",
+			time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 6, 0, 0, 0, 0, time.UTC)},
+		{"Mon 09:00 - Wed 17:00",
+			time.Date(2006, 1, 2, 9, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 4, 17, 0, 0, 0, time.UTC)},
+		{"Mon 17:00 - 09:00",
+			time.Date(2006, 1, 2, 17, 0, 0, 0, time.UTC),
+			time.Date(2006, 1, 3, 9, 0, 0, 0, time.UTC)},
+	}
+
+	p := For(locale.EnUS)
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			start, end, err := p.ParseRange(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			s, e, err := p.TimeRange(start, end, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !s.Equal(test.start) || !e.Equal(test.end) {
+				t.Errorf("\n have: %v - %v \n want: %v - %v", s, e, test.start, test.end)
+			}
+		})
+	}
+}
@@ -0,0 +1,246 @@
+package ptime
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// parseRelative checks to see if the current token starts a word or phrase
+// that is used in a relative date. Returns true if the token was consumed.
+func (p *parseContext) parseRelative() (bool, error) {
+	if p.state == done {
+		return false, nil
+	}
+	if name, n, ok := p.matchRelativeDay(); ok {
+		p.trace("is relative day")
+		if p.parsed.Relative != "" || p.relSign != 0 {
+			return true, p.err(ErrInvalidRelative, "Relative", "unexpected relative day: %v", name)
+		}
+		first := p.tok
+		p.skip(n)
+		p.parsed.Relative = formatRelative(p.loc.RelativeDayNames[name])
+		p.parsed.RelativeUnit = p.loc.UnitNames.Main(locale.Days)
+		p.mark("Relative", p.span(first, p.tok))
+		p.mark("RelativeUnit", p.span(first, p.tok))
+		if p.state == unknown || p.state == parsingDate {
+			p.changeState(parsingTime)
+		}
+		return true, nil
+	}
+
+	sign := 0
+	n := 0
+	if _, n = p.matchNames(p.loc.NextNames); n > 0 {
+		p.trace("is next")
+		sign = 1
+	} else if _, n = p.matchNames(p.loc.LastNames); n > 0 {
+		p.trace("is last")
+		sign = -1
+	} else if _, n = p.matchNames(p.loc.FutureNames); n > 0 {
+		p.trace("is future")
+		sign = 1
+	} else if _, n = p.matchNames(p.loc.PastNames); n > 0 {
+		p.trace("is past")
+		sign = -1
+	}
+	if sign != 0 {
+		if p.relSign != 0 || p.parsed.Relative != "" {
+			return true, p.err(ErrInvalidRelative, "Relative", "unexpected relative text: %v", p.tok.Val)
+		}
+		first := p.tok
+		p.skip(n)
+		p.relSign = sign
+		p.markRelative(first)
+		p.endRelativeWeekday()
+		return true, nil
+	}
+
+	if unit, ok := lookupUnit(p.loc, p.tok.Val); ok {
+		p.trace("is relative unit")
+		if p.parsed.RelativeUnit != "" {
+			return true, p.err(ErrInvalidRelative, "RelativeUnit", "unexpected relative unit: %v", p.tok.Val)
+		}
+		p.parsed.RelativeUnit = unit
+		p.mark("RelativeUnit", p.tok)
+		return true, nil
+	}
+	return false, nil
+}
+
+// endRelative combines the direction, amount, and unit found while parsing
+// into a single relative value.
+func (p *parseContext) endRelative() error {
+	if p.parsed.Relative != "" {
+		return nil
+	}
+	if p.relSign == 0 {
+		if p.parsed.RelativeUnit != "" {
+			return p.err(ErrInvalidRelative, "Relative", "missing direction for relative unit: %v", p.parsed.RelativeUnit)
+		}
+		return nil
+	}
+	if p.parsed.RelativeUnit == "" && p.parsed.Weekday == "" {
+		return p.err(ErrInvalidRelative, "Relative", "incomplete relative date")
+	}
+	amount := 1
+	if p.relAmount != "" {
+		var err error
+		amount, err = strconv.Atoi(p.relAmount)
+		if err != nil {
+			return p.err(ErrInvalidRelative, "Relative", "invalid relative amount: %v", p.relAmount)
+		}
+	}
+	p.parsed.Relative = formatRelative(p.relSign * amount)
+	p.mark("Relative", p.span(p.relFirst, p.relLast))
+	return nil
+}
+
+// endRelativeWeekday changes to parsing the time once a weekday has a
+// direction, as in "next Friday" or "vendredi prochain", since that is the
+// whole date. This lets "next Friday 5pm" be read like "tomorrow 3pm".
+func (p *parseContext) endRelativeWeekday() {
+	if p.relSign == 0 || p.parsed.Weekday == "" || p.parsed.RelativeUnit != "" {
+		return
+	}
+	if p.state == unknown || p.state == parsingDate {
+		p.changeState(parsingTime)
+	}
+}
+
+// markRelative remembers the first and last tokens used for the direction
+// and amount of a relative date. The tokens of a phrase start at first and
+// end at the current token.
+func (p *parseContext) markRelative(first Token) {
+	if p.relFirst.Type == End {
+		p.relFirst = first
+	}
+	p.relLast = p.tok
+}
+
+func (p *parseContext) matchRelativeDay() (string, int, bool) {
+	var names []string
+	for name := range p.loc.RelativeDayNames {
+		names = append(names, name)
+	}
+	name, n := p.matchNames(names)
+	return name, n, n > 0
+}
+
+// matchNames returns the name that matches the most tokens starting with
+// the current token and the number of tokens matched.
+func (p *parseContext) matchNames(names []string) (string, int) {
+	var match string
+	var matchLen int
+	for _, name := range names {
+		if n := p.matchPhrase(name); n > matchLen {
+			match = name
+			matchLen = n
+		}
+	}
+	return match, matchLen
+}
+
+// matchPhrase checks to see if the tokens starting with the current token
+// match those found in the phrase. Returns the number of tokens matched or
+// zero if there is no match.
+func (p *parseContext) matchPhrase(phrase string) int {
+	want := scan(normalizeText(phrase))
+	if len(want) == 0 {
+		return 0
+	}
+	for i, w := range want {
+		have := p.lookahead(i)
+		if have.Type != w.Type || p.loc.Key(have.Val) != p.loc.Key(w.Val) {
+			return 0
+		}
+	}
+	return len(want)
+}
+
+// skip advances past the remaining tokens of a phrase that is n tokens long
+func (p *parseContext) skip(n int) {
+	for i := 1; i < n; i++ {
+		p.next()
+	}
+}
+
+func formatRelative(n int) string {
+	if n == 0 {
+		return "0"
+	}
+	return fmt.Sprintf("%+d", n)
+}
+
+func lookupUnit(l *locale.Locale, text string) (string, bool) {
+	n, ok := l.UnitNum[l.Key(text)]
+	if !ok {
+		return "", false
+	}
+	return l.UnitNames.Main(n), true
+}
+
+// relativeTime returns the reference time moved by the relative amount
+// found in the parsed value.
+func relativeTime(l *locale.Locale, p Parsed, now time.Time) (time.Time, error) {
+	n, err := strconv.Atoi(p.Relative)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid relative amount: %v", p.Relative)
+	}
+	if p.Year != "" || p.Month != "" || p.Day != "" {
+		return time.Time{}, fmt.Errorf("relative date cannot be used with an absolute date")
+	}
+
+	if p.RelativeUnit == "" {
+		wd, ok := l.DayNum[l.Key(p.Weekday)]
+		if !ok {
+			return time.Time{}, fmt.Errorf("relative date requires a unit or weekday")
+		}
+		var days int
+		if n > 0 {
+			days = (wd - int(now.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			days += (n - 1) * 7
+		} else if n < 0 {
+			days = (int(now.Weekday()) - wd + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			days = -days + (n+1)*7
+		}
+		return now.AddDate(0, 0, days), nil
+	}
+
+	unit, ok := l.UnitNum[l.Key(p.RelativeUnit)]
+	if !ok {
+		return time.Time{}, fmt.Errorf("invalid relative unit: %v", p.RelativeUnit)
+	}
+	if isClockUnit(unit) && p.Hour != "" {
+		return time.Time{}, fmt.Errorf("relative %v cannot be used with an hour", p.RelativeUnit)
+	}
+	switch unit {
+	case locale.Seconds:
+		return now.Add(time.Duration(n) * time.Second), nil
+	case locale.Minutes:
+		return now.Add(time.Duration(n) * time.Minute), nil
+	case locale.Hours:
+		return now.Add(time.Duration(n) * time.Hour), nil
+	case locale.Days:
+		return now.AddDate(0, 0, n), nil
+	case locale.Weeks:
+		return now.AddDate(0, 0, n*7), nil
+	case locale.Months:
+		return now.AddDate(0, n, 0), nil
+	case locale.Years:
+		return now.AddDate(n, 0, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid relative unit: %v", p.RelativeUnit)
+}
+
+func isClockUnit(unit int) bool {
+	return unit == locale.Seconds || unit == locale.Minutes || unit == locale.Hours
+}
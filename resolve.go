@@ -0,0 +1,208 @@
+package ptime
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Resolve is the policy used to fill in the missing parts of an
+// incomplete date, such as "Friday" or "Dec 25".
+type Resolve int
+
+const (
+	// ResolveCurrent takes the missing year, month, or day from the
+	// reference time. A weekday given by itself is ignored. This is the
+	// default.
+	ResolveCurrent Resolve = iota
+	// ResolveNearest uses the date that is closest to the reference time
+	ResolveNearest
+	// ResolveNext uses the first date on or after the reference time
+	ResolveNext
+	// ResolvePrevious uses the last date on or before the reference time
+	ResolvePrevious
+)
+
+func (r Resolve) String() string {
+	switch r {
+	case ResolveNearest:
+		return "nearest"
+	case ResolveNext:
+		return "next"
+	case ResolvePrevious:
+		return "previous"
+	}
+	return "current"
+}
+
+// resolveDate moves an incomplete date to the one chosen by the policy.
+// Dates are compared at the level of the largest missing field so that
+// "March" is still this March on the 15th of March. The date given is
+// returned as-is if it is complete or relative.
+func (p *Parser) resolveDate(parsed Parsed, weekday int, year int, mon int, day int, now time.Time) (int, int, int) {
+	if p.Resolve == ResolveCurrent || parsed.Relative != "" || parsed.Year != "" || parsed.Week != "" {
+		return year, mon, day
+	}
+	date := func(y int, m int, d int) time.Time {
+		return time.Date(y, time.Month(m), d, 0, 0, 0, 0, time.UTC)
+	}
+	ref := date(now.Year(), int(now.Month()), now.Day())
+
+	var candidates []time.Time
+	switch {
+	case parsed.Month != "" && parsed.Day != "":
+		// Skip years that do not have the date, such as February 29
+		for _, y := range []int{year - 1, year, year + 1} {
+			if d := date(y, mon, day); d.Day() == day {
+				candidates = append(candidates, d)
+			}
+		}
+	case parsed.Month != "":
+		ref = date(now.Year(), int(now.Month()), 1)
+		for _, y := range []int{year - 1, year, year + 1} {
+			candidates = append(candidates, date(y, mon, 1))
+		}
+	case parsed.Day != "" && len(parsed.Day) != 3:
+		// Skip months that do not have the day so that "the 31st" is not
+		// moved into the month that follows
+		for _, m := range []int{mon - 1, mon, mon + 1} {
+			if d := date(year, m, day); d.Day() == day {
+				candidates = append(candidates, d)
+			}
+		}
+	case parsed.Weekday != "" && parsed.Day == "":
+		ahead := (weekday - int(now.Weekday()) + 7) % 7
+		candidates = append(candidates, ref.AddDate(0, 0, ahead-7), ref.AddDate(0, 0, ahead))
+	}
+	if len(candidates) == 0 {
+		return year, mon, day
+	}
+
+	chosen := p.Resolve.choose(ref, candidates)
+	return chosen.Year(), int(chosen.Month()), chosen.Day()
+}
+
+// choose picks a date from the candidates which are in order
+func (r Resolve) choose(ref time.Time, candidates []time.Time) time.Time {
+	switch r {
+	case ResolveNext:
+		for _, c := range candidates {
+			if !c.Before(ref) {
+				return c
+			}
+		}
+	case ResolvePrevious:
+		for i := len(candidates) - 1; i >= 0; i-- {
+			if !candidates[i].After(ref) {
+				return candidates[i]
+			}
+		}
+	case ResolveNearest:
+		best := candidates[0]
+		for _, c := range candidates[1:] {
+			if absDuration(c.Sub(ref)) < absDuration(best.Sub(ref)) {
+				best = c
+			}
+		}
+		return best
+	}
+	return candidates[len(candidates)-1]
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// validateDate makes sure that the date exists in the calendar unless
+// the parser is set to normalize dates.
+func (p *Parser) validateDate(parsed Parsed, year int, mon int, day int) error {
+	if p.Normalize {
+		return nil
+	}
+	dateErr := func(field string, format string, a ...any) error {
+		return &DateError{Field: field, Year: year, Month: mon, Day: day, Msg: fmt.Sprintf(format, a...)}
+	}
+	leap := isLeap(year)
+
+	// Day of the year
+	if len(parsed.Day) == 3 {
+		days := 365
+		if leap {
+			days = 366
+		}
+		switch {
+		case day < 1:
+			return dateErr("Day", "invalid day of the year: %v", day)
+		case day == 366 && !leap:
+			return dateErr("Day", "day 366 is not in %v, which is not a leap year", year)
+		case day > days:
+			return dateErr("Day", "day %v is not in %v, which has %v days", day, year, days)
+		}
+		return nil
+	}
+
+	if mon < 1 || mon > 12 {
+		return dateErr("Month", "invalid month: %v", mon)
+	}
+	days := daysIn(time.Month(mon), year)
+	switch {
+	case day < 1:
+		return dateErr("Day", "invalid day: %v", day)
+	case mon == int(time.February) && day == 29 && !leap:
+		return dateErr("Day", "February 29 is not in %v, which is not a leap year", year)
+	case day > days:
+		return dateErr("Day", "day %v is not in %v %v, which has %v days", day, time.Month(mon), year, days)
+	}
+	return nil
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// daysIn returns the number of days in the month
+func daysIn(mon time.Month, year int) int {
+	return time.Date(year, mon+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// WeekdayMode is what to do when a weekday is given along with a date
+type WeekdayMode int
+
+const (
+	// WeekdayCheck returns a WeekdayError if the weekday does not match
+	// the date. A date without a year, such as "Mon, Jan 2", is not checked
+	// since the year comes from the reference time. This is the default.
+	WeekdayCheck WeekdayMode = iota
+	// WeekdayIgnore does not look at the weekday
+	WeekdayIgnore
+	// WeekdayRepair swaps a numeric month and day, as in reading "3/4" as
+	// April 3 instead of March 4, when that makes the weekday match. Only
+	// dates separated by a slash or a space are swapped since a year-first
+	// date, such as "2006-01-02", is not ambiguous. If the weekday still
+	// does not match, a WeekdayError is returned.
+	WeekdayRepair
+)
+
+// checkWeekday makes sure the weekday matches the date. Returns the date
+// to use, which is only different when repaired.
+func (p *Parser) checkWeekday(parsed Parsed, weekday int, year int, mon int, day int) (int, int, int, error) {
+	if p.WeekdayMode == WeekdayIgnore || weekday < 0 || parsed.Year == "" || parsed.Day == "" || parsed.Relative != "" {
+		return year, mon, day, nil
+	}
+	date := time.Date(year, time.Month(mon), day, 0, 0, 0, 0, time.UTC)
+	if int(date.Weekday()) == weekday {
+		return year, mon, day, nil
+	}
+	if p.WeekdayMode == WeekdayRepair && len(parsed.Day) != 3 && (parsed.DateSep == "/" || parsed.DateSep == " ") {
+		if _, err := strconv.Atoi(parsed.Month); err == nil && day <= 12 {
+			swapped := time.Date(year, time.Month(day), mon, 0, 0, 0, 0, time.UTC)
+			if swapped.Day() == mon && int(swapped.Weekday()) == weekday {
+				return year, day, mon, nil
+			}
+		}
+	}
+	return year, mon, day, &WeekdayError{Weekday: parsed.Weekday, Date: date}
+}
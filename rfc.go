@@ -0,0 +1,201 @@
+package ptime
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// Standards recognized when the RFC option is set on the parser
+const (
+	RFC3339 = "RFC3339"
+	RFC2822 = "RFC2822"
+	RFC1123 = "RFC1123"
+)
+
+var (
+	rfc3339Pattern = regexp.MustCompile(`^(\d{4})-(\d{2})-(\d{2})([Tt ])(\d{2}):(\d{2}):(\d{2})(?:\.(\d+))?([Zz]|[+-]\d{2}:\d{2})$`)
+	rfc2822Pattern = regexp.MustCompile(`^(?:([A-Za-z]{3})\s*,\s*)?(\d{1,2})\s+([A-Za-z]{3})\s+(\d{2,})\s+(\d{2})\s*:\s*(\d{2})(?:\s*:\s*(\d{2}))?\s+([+-]\d{4}|[A-Za-z]{1,3})$`)
+)
+
+// Names used in RFC 2822 dates are always in English regardless of the
+// locale.
+var (
+	rfcDayNames   = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+	rfcMonthNames = []string{"jan", "feb", "mar", "apr", "may", "jun", "jul", "aug", "sep", "oct", "nov", "dec"}
+)
+
+// Obsolete zone names from RFC 2822, section 4.3
+var rfcZones = map[string]string{
+	"UT":  "+0000",
+	"GMT": "+0000",
+	"EST": "-0500",
+	"EDT": "-0400",
+	"CST": "-0600",
+	"CDT": "-0500",
+	"MST": "-0700",
+	"MDT": "-0600",
+	"PST": "-0800",
+	"PDT": "-0700",
+}
+
+// parseRFC checks to see if the text is in one of the layouts defined by
+// RFC 3339, RFC 2822, or RFC 1123. Returns false if it is not and the text
+// should be given to the general parser instead.
+func parseRFC(l *locale.Locale, text string) (Parsed, bool) {
+	if parsed, ok := parseRFC3339(text); ok {
+		return parsed, true
+	}
+	return parseRFC2822(l, text)
+}
+
+func parseRFC3339(text string) (Parsed, bool) {
+	m := rfc3339Pattern.FindStringSubmatch(text)
+	if m == nil {
+		return Parsed{}, false
+	}
+	if !inRange(m[2], 1, 12) || !inRange(m[3], 1, 31) || !inRange(m[5], 0, 23) ||
+		!inRange(m[6], 0, 59) || !inRange(m[7], 0, 60) {
+		return Parsed{}, false
+	}
+	parsed := Parsed{
+		Year:        m[1],
+		Month:       m[2],
+		Day:         m[3],
+		Hour:        m[5],
+		Minute:      m[6],
+		Second:      m[7],
+		FracSecond:  m[8],
+		DateSep:     "-",
+		TimeSep:     ":",
+		DateTimeSep: strings.ToUpper(m[4]),
+		Standard:    RFC3339,
+	}
+	if strings.EqualFold(m[9], "Z") {
+		parsed.Zone = "Z"
+		parsed.Offset = "+0000"
+	} else {
+		parsed.Offset = strings.Replace(m[9], ":", "", 1)
+	}
+	return parsed, true
+}
+
+func parseRFC2822(l *locale.Locale, text string) (Parsed, bool) {
+	stripped, ok := stripComments(text)
+	if !ok {
+		return Parsed{}, false
+	}
+	m := rfc2822Pattern.FindStringSubmatch(stripped)
+	if m == nil {
+		return Parsed{}, false
+	}
+	parsed := Parsed{
+		Day:      m[2],
+		Hour:     m[5],
+		Minute:   m[6],
+		Second:   m[7],
+		DateSep:  " ",
+		TimeSep:  ":",
+		Standard: RFC2822,
+	}
+	if m[1] != "" {
+		wd := indexOf(rfcDayNames, strings.ToLower(m[1]))
+		if wd < 0 {
+			return Parsed{}, false
+		}
+		parsed.Weekday = l.DayNamesAbbr[wd]
+	}
+	mon := indexOf(rfcMonthNames, strings.ToLower(m[3]))
+	if mon < 0 {
+		return Parsed{}, false
+	}
+	parsed.Month = l.MonthNamesAbbr[mon]
+
+	// Obsolete two and three digit years
+	year, _ := strconv.Atoi(m[4])
+	switch {
+	case len(m[4]) == 2 && year < 50:
+		year += 2000
+	case len(m[4]) < 4:
+		year += 1900
+	}
+	parsed.Year = fmt.Sprintf("%04d", year)
+
+	if !inRange(m[2], 1, 31) || !inRange(m[5], 0, 23) || !inRange(m[6], 0, 59) ||
+		(m[7] != "" && !inRange(m[7], 0, 60)) {
+		return Parsed{}, false
+	}
+
+	zone := strings.ToUpper(m[8])
+	switch {
+	case zone[0] == '+' || zone[0] == '-':
+		parsed.Offset = zone
+	case rfcZones[zone] != "":
+		parsed.Zone = zone
+		parsed.Offset = rfcZones[zone]
+	case len(zone) == 1 && zone != "J":
+		// Military zones were defined with the wrong sign in RFC 822 and
+		// should be treated as an unknown offset
+		parsed.Zone = zone
+		parsed.Offset = "-0000"
+	default:
+		return Parsed{}, false
+	}
+
+	// An HTTP date as defined in RFC 7231 is the fixed length subset of
+	// RFC 1123 that is always in GMT.
+	fixdate := fmt.Sprintf("%v, %v %v %v %v:%v:%v GMT", m[1], m[2], m[3], m[4], m[5], m[6], m[7])
+	if m[1] != "" && len(m[2]) == 2 && len(m[4]) == 4 && m[7] != "" && text == fixdate {
+		parsed.Standard = RFC1123
+	}
+	return parsed, true
+}
+
+// stripComments removes comments, which may be nested, and replaces them
+// with a single space. Returns false if the comments are not balanced.
+func stripComments(text string) (string, bool) {
+	var b strings.Builder
+	depth := 0
+	escaped := false
+	for _, ch := range text {
+		switch {
+		case escaped:
+			escaped = false
+		case depth > 0 && ch == '\\':
+			escaped = true
+		case ch == '(':
+			if depth == 0 {
+				b.WriteRune(' ')
+			}
+			depth++
+		case ch == ')':
+			if depth == 0 {
+				return "", false
+			}
+			depth--
+		case depth == 0:
+			b.WriteRune(ch)
+		}
+	}
+	if depth != 0 {
+		return "", false
+	}
+	return strings.TrimSpace(b.String()), true
+}
+
+func inRange(v string, min int, max int) bool {
+	n, err := strconv.Atoi(v)
+	return err == nil && n >= min && n <= max
+}
+
+func indexOf(list []string, v string) int {
+	for i, item := range list {
+		if item == v {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,105 @@
+package ptime
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestParserRFC(t *testing.T) {
+	tests := []struct {
+		loc    *locale.Locale
+		text   string
+		parsed Parsed
+	}{
+		{locale.EnUS, "2006-01-02T15:04:05Z", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Zone: "Z", Offset: "+0000", DateSep: "-", TimeSep: ":", DateTimeSep: "T", Standard: RFC3339,
+		}},
+		{locale.EnUS, "2006-01-02t15:04:05.999999999-07:00", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05", FracSecond: "999999999",
+			Offset: "-0700", DateSep: "-", TimeSep: ":", DateTimeSep: "T", Standard: RFC3339,
+		}},
+		{locale.EnUS, "Mon, 02 Jan 2006 15:04:05 -0700", Parsed{
+			Weekday: "Mon", Year: "2006", Month: "Jan", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Offset: "-0700", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+		{locale.EnUS, "Mon, 02 Jan 2006 15:04:05 GMT", Parsed{
+			Weekday: "Mon", Year: "2006", Month: "Jan", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Zone: "GMT", Offset: "+0000", DateSep: " ", TimeSep: ":", Standard: RFC1123,
+		}},
+		{locale.EnUS, "02 Jan 2006 15:04:05 GMT", Parsed{
+			Year: "2006", Month: "Jan", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Zone: "GMT", Offset: "+0000", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+		{locale.EnUS, "2 Jan 06 15:04 EST", Parsed{
+			Year: "2006", Month: "Jan", Day: "2", Hour: "15", Minute: "04",
+			Zone: "EST", Offset: "-0500", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+		{locale.EnUS, "Mon (Monday), 2 Jan 106 15 : 04 : 05 (local (daylight) time) -0700", Parsed{
+			Weekday: "Mon", Year: "2006", Month: "Jan", Day: "2", Hour: "15", Minute: "04", Second: "05",
+			Offset: "-0700", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+		{locale.EnUS, "2 Jan 99 15:04 a", Parsed{
+			Year: "1999", Month: "Jan", Day: "2", Hour: "15", Minute: "04",
+			Zone: "A", Offset: "-0000", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+		{locale.FrFR, "Mon, 02 Jan 2006 15:04:05 +0100", Parsed{
+			Weekday: "lun.", Year: "2006", Month: "janv.", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			Offset: "+0100", DateSep: " ", TimeSep: ":", Standard: RFC2822,
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			p := NewParser(test.loc)
+			p.RFC = true
+			parsed, err := p.Parse(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, test.parsed) {
+				t.Errorf("\n have: %v \n want: %v", parsed, test.parsed)
+			}
+		})
+	}
+}
+
+func TestParserRFCFallback(t *testing.T) {
+	tests := []string{
+		"Jan 2 2006",
+		"2006-13-02T15:04:05Z",
+		"Mon, 02 Jan 2006 15:04:05 (unbalanced -0700",
+		"02 Jan 2006 15:04:05 J",
+	}
+
+	p := NewParser(locale.EnUS)
+	p.RFC = true
+	for _, text := range tests {
+		t.Run(text, func(t *testing.T) {
+			parsed, _ := p.Parse(text)
+			if parsed.Standard != "" {
+				t.Errorf("unexpected standard: %v", parsed.Standard)
+			}
+		})
+	}
+}
+
+func TestTimeRFC(t *testing.T) {
+	p := For(locale.EnUS)
+	p.Parser.RFC = true
+	parsed, err := p.Parse("Sun, 06 Nov 1994 08:49:37 GMT")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	have, err := p.Time(parsed, time.Now())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)
+	if !have.Equal(want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
@@ -1,6 +1,7 @@
 package ptime
 
 import (
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -12,16 +13,30 @@ type scanner struct {
 	src    string
 	n      int
 	ch     rune
-	w      int
+	w      int // width of ch in bytes, used to advance to the next rune
 	idx    int
 	inWord bool
 }
 
+// Scan splits the text into tokens. Digits and full-width forms are read
+// as ASCII, see normalizeText, but the value and position of each token
+// are from the original text.
 func Scan(text string) []Token {
+	norm, offsets := normalize(text)
+	tokens := scan(norm)
+	for i, tok := range tokens {
+		tokens[i] = sourceToken(text, offsets, tok)
+	}
+	return tokens
+}
+
+// scan splits text that has already been normalized into tokens
+func scan(text string) []Token {
 	s := scanner{
 		src: text,
 		n:   len(text),
 		idx: -1,
+		w:   1,
 	}
 	s.scan()
 	var tokens []Token
@@ -31,6 +46,67 @@ func Scan(text string) []Token {
 	return tokens
 }
 
+// normalizeText replaces digits from other numbering systems, such as "٢"
+// or "२", with ASCII digits and full-width forms, such as "２" or "：", with
+// their ASCII versions. The ideographic space becomes a regular space.
+// Each character is replaced by a single character.
+func normalizeText(text string) string {
+	return strings.Map(normalizeRune, text)
+}
+
+// normalize is like normalizeText and also returns the byte offset in the
+// original text for each byte in the normalized text, followed by the
+// length of the original text. The offsets are nil when the text did not
+// change.
+func normalize(text string) (string, []int) {
+	norm := normalizeText(text)
+	if norm == text {
+		return text, nil
+	}
+	offsets := make([]int, 0, len(norm)+1)
+	for i, ch := range text {
+		for n := utf8.RuneLen(normalizeRune(ch)); n > 0; n-- {
+			offsets = append(offsets, i)
+		}
+	}
+	return norm, append(offsets, len(text))
+}
+
+// sourceToken returns the token with the value and position it has in the
+// original text, using the offsets from normalize.
+func sourceToken(src string, offsets []int, tok Token) Token {
+	if offsets == nil || tok.Pos < 1 || tok.Pos-1+len(tok.Val) >= len(offsets) {
+		return tok
+	}
+	start := offsets[tok.Pos-1]
+	end := offsets[tok.Pos-1+len(tok.Val)]
+	return Token{tok.Type, src[start:end], start + 1}
+}
+
+func normalizeRune(ch rune) rune {
+	switch {
+	case ch < utf8.RuneSelf:
+		return ch
+	case ch >= '\uff01' && ch <= '\uff5e':
+		return ch - '\uff01' + '!'
+	case ch == '\u3000':
+		return ' '
+	case unicode.IsDigit(ch):
+		return '0' + digitValue(ch)
+	}
+	return ch
+}
+
+// digitValue returns the value of a decimal digit. The decimal digits in
+// Unicode are found in runs of ten that start with zero.
+func digitValue(ch rune) rune {
+	zero := ch
+	for unicode.IsDigit(zero - 1) {
+		zero--
+	}
+	return (ch - zero) % 10
+}
+
 func (s *scanner) next() Token {
 	if s.ch == end {
 		return Token{End, "", s.idx}
@@ -55,9 +131,17 @@ func (s *scanner) scanNumber() Token {
 
 func (s *scanner) scanText() Token {
 	start := s.idx
-	for unicode.IsLetter(s.ch) {
+	// Combining marks are part of the word, such as the accent in a
+	// decomposed "é"
+	for unicode.IsLetter(s.ch) || unicode.Is(unicode.Mn, s.ch) {
 		s.scan()
 	}
+	// Time zone names from the IANA database, e.g. America/New_York
+	if s.ch == '/' && unicode.IsLetter(s.peek()) {
+		for unicode.IsLetter(s.ch) || s.ch == '/' || s.ch == '_' || (s.ch == '-' && unicode.IsLetter(s.peek())) {
+			s.scan()
+		}
+	}
 	return Token{Text, s.src[start:s.idx], start + 1}
 }
 
@@ -84,7 +168,7 @@ func (s *scanner) scan() {
 	if s.ch == end {
 		return
 	}
-	s.idx++
+	s.idx += s.w
 	if s.idx >= len(s.src) {
 		s.ch = end
 		s.idx = s.n
@@ -92,3 +176,11 @@ func (s *scanner) scan() {
 	}
 	s.ch, s.w = utf8.DecodeRuneInString(s.src[s.idx:])
 }
+
+func (s *scanner) peek() rune {
+	if s.ch == end || s.idx+s.w >= len(s.src) {
+		return end
+	}
+	ch, _ := utf8.DecodeRuneInString(s.src[s.idx+s.w:])
+	return ch
+}
@@ -31,14 +31,114 @@ func TestScanner(t *testing.T) {
 			{Indicator, "-", 6},
 			{Number, "06", 7},
 		}},
+		{"2 févr. 2006", []Token{
+			{Number, "2", 1},
+			{Text, "févr", 3},
+			{Indicator, ".", 8},
+			{Number, "2006", 10},
+		}},
+		{"2 fe\u0301vr", []Token{
+			{Number, "2", 1},
+			{Text, "fe\u0301vr", 3},
+		}},
+		{"3pm America/New_York", []Token{
+			{Number, "3", 1},
+			{Text, "pm", 2},
+			{Text, "America/New_York", 5},
+		}},
+		{"America/Port-au-Prince", []Token{
+			{Text, "America/Port-au-Prince", 1},
+		}},
+		{"٢٠٢٣/١/٢", []Token{
+			{Number, "٢٠٢٣", 1},
+			{Indicator, "/", 9},
+			{Number, "١", 10},
+			{Indicator, "/", 12},
+			{Number, "٢", 13},
+		}},
+		{"２０２３－０１　ＰＭ", []Token{
+			{Number, "２０２３", 1},
+			{Indicator, "－", 13},
+			{Number, "０１", 16},
+			{Text, "ＰＭ", 25},
+		}},
+		{"２０２３-01-02", []Token{
+			{Number, "２０２３", 1},
+			{Indicator, "-", 13},
+			{Number, "01", 14},
+			{Indicator, "-", 16},
+			{Number, "02", 17},
+		}},
+		{"Jan/2/2006", []Token{
+			{Text, "Jan", 1},
+			{Indicator, "/", 4},
+			{Number, "2", 5},
+			{Indicator, "/", 6},
+			{Number, "2006", 7},
+		}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			tokens := Scan(test.text)
+			if !reflect.DeepEqual(tokens, test.tokens) {
+				t.Errorf("\n have: %v \n want: %v", tokens, test.tokens)
+			}
+		})
 	}
+}
 
+func TestNormalizeText(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"2006-01-02", "2006-01-02"},
+		{"٢٠٠٦", "2006"},
+		{"۲۰۰۶", "2006"},
+		{"२००६", "2006"},
+		{"๒๕๖๖", "2566"},
+		{"𝟐𝟎𝟎𝟔", "2006"},
+		{"２００６年１月２日", "2006年1月2日"},
+		{"１５：０４　ＰＭ", "15:04 PM"},
+		{"février", "février"},
+	}
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			if have := normalizeText(test.text); have != test.want {
+				t.Errorf("\n have: %q \n want: %q", have, test.want)
+			}
+		})
+	}
+}
+
+func TestScanRunes(t *testing.T) {
+	tests := []struct {
+		text   string
+		tokens []Token
+	}{
+		{"Łódź 5", []Token{{Text, "Łódź", 1}, {Number, "5", 9}}},
+		{"2006年1月2日", []Token{
+			{Number, "2006", 1},
+			{Text, "年", 5},
+			{Number, "1", 8},
+			{Text, "月", 9},
+			{Number, "2", 12},
+			{Text, "日", 13},
+		}},
+		{"3 → 4", []Token{{Number, "3", 1}, {Indicator, "→", 3}, {Number, "4", 7}}},
+	}
 	for _, test := range tests {
 		t.Run(test.text, func(t *testing.T) {
 			tokens := Scan(test.text)
 			if !reflect.DeepEqual(tokens, test.tokens) {
 				t.Errorf("\n have: %v \n want: %v", tokens, test.tokens)
 			}
+			for _, tok := range tokens {
+				if test.text[tok.Pos-1:tok.End()-1] != tok.Val {
+					t.Errorf("token %v is not in the text", tok)
+				}
+			}
 		})
 	}
 }
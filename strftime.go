@@ -0,0 +1,360 @@
+package ptime
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+// strftimeTable maps strftime directives to a field and format in the
+// format table.
+var strftimeTable = map[string]string{
+	"a":  "weekday/abbr",
+	"A":  "weekday/wide",
+	"b":  "month/abbr",
+	"B":  "month/wide",
+	"d":  "day/02",
+	"e":  "day/2",
+	"f":  "fraction/6",
+	"G":  "year/week",
+	"h":  "month/abbr",
+	"H":  "hour/02",
+	"I":  "hour/12-02",
+	"j":  "day/year",
+	"l":  "hour/12-2",
+	"m":  "month/02",
+	"M":  "minute",
+	"p":  "period/abbr",
+	"S":  "second",
+	"u":  "weekday/iso",
+	"V":  "week/02",
+	"y":  "year/2",
+	"Y":  "year",
+	"z":  "offset",
+	":z": "offset/:",
+	"Z":  "zone",
+}
+
+// strftimeAliases are directives that are shorthand for other directives.
+// The date and time representations for %c, %x, and %X are those from the
+// C locale.
+var strftimeAliases = map[string]string{
+	"c": "%a %b %e %H:%M:%S %Y",
+	"D": "%m/%d/%y",
+	"F": "%Y-%m-%d",
+	"n": "\n",
+	"R": "%H:%M",
+	"t": "\t",
+	"T": "%H:%M:%S",
+	"x": "%m/%d/%y",
+	"X": "%H:%M:%S",
+	"%": "%",
+}
+
+// FormatStrftime formats the time using a layout with strftime directives
+// such as "%Y-%m-%d %H:%M:%S". Names for %a, %A, %b, %B, and %p come from
+// the locale.
+func FormatStrftime(loc *locale.Locale, layout string, t time.Time) string {
+	return string(appendStrftime(nil, loc, layout, t))
+}
+
+func appendStrftime(b []byte, loc *locale.Locale, layout string, t time.Time) []byte {
+	for i := 0; i < len(layout); i++ {
+		if layout[i] != '%' {
+			b = append(b, layout[i])
+			continue
+		}
+		d := strftimeDirective(layout, i)
+		i += len(d)
+		if alias, ok := strftimeAliases[d]; ok {
+			if d == "%" || d == "n" || d == "t" {
+				b = append(b, alias...)
+			} else {
+				b = appendStrftime(b, loc, alias, t)
+			}
+			continue
+		}
+		field, ok := strftimeTable[d]
+		if !ok {
+			b = append(b, badField...)
+			continue
+		}
+		name, format, _ := strings.Cut(field, "/")
+		b = formatTable[name](b, loc, format, t)
+	}
+	return b
+}
+
+// strftimeDirective returns the directive that follows the '%' at index i
+// in the layout.
+func strftimeDirective(layout string, i int) string {
+	if i+1 >= len(layout) {
+		return ""
+	}
+	if layout[i+1] == ':' && i+2 < len(layout) {
+		return layout[i+1 : i+3]
+	}
+	return layout[i+1 : i+2]
+}
+
+type strptimeParser struct {
+	loc    *locale.Locale
+	text   string
+	pos    int
+	parsed Parsed
+}
+
+// ParseStrptime parses text that must exactly match a layout with strptime
+// directives such as "%Y-%m-%d %H:%M:%S". Whitespace in the layout matches
+// one or more whitespace characters in the text. Names for %a, %A, %b, %B,
+// and %p come from the locale. Digits from other numbering systems and
+// full-width forms are read as ASCII, as they are by Parse, and positions
+// in errors are from the original text.
+func ParseStrptime(loc *locale.Locale, layout string, text string) (Parsed, error) {
+	norm, offsets := normalize(text)
+	sp := &strptimeParser{loc: loc, text: norm}
+	err := sp.parse(normalizeText(layout))
+	if err == nil && sp.pos < len(norm) {
+		rest := sourceToken(text, offsets, Token{Text, norm[sp.pos:], sp.pos + 1})
+		err = sp.err(ErrUnexpectedText, "", "unexpected text: %v", rest.Val)
+	}
+	var perr *ParseError
+	if errors.As(err, &perr) {
+		perr.Token = sourceToken(text, offsets, perr.Token)
+	}
+	return sp.parsed, err
+}
+
+func (sp *strptimeParser) parse(layout string) error {
+	for i := 0; i < len(layout); i++ {
+		ch := layout[i]
+		switch {
+		case ch == '%':
+			d := strftimeDirective(layout, i)
+			i += len(d)
+			if err := sp.parseDirective(d); err != nil {
+				return err
+			}
+		case ch == ' ' || ch == '\t' || ch == '\n':
+			start := sp.pos
+			for sp.pos < len(sp.text) {
+				ch, w := utf8.DecodeRuneInString(sp.text[sp.pos:])
+				if !unicode.IsSpace(ch) {
+					break
+				}
+				sp.pos += w
+			}
+			if sp.pos == start {
+				return sp.err(ErrUnexpectedText, "", "expecting space")
+			}
+		default:
+			if sp.pos >= len(sp.text) || sp.text[sp.pos] != ch {
+				return sp.err(ErrUnexpectedText, "", "expecting '%c'", ch)
+			}
+			sp.pos++
+		}
+	}
+	return nil
+}
+
+func (sp *strptimeParser) parseDirective(d string) error {
+	switch d {
+	case "a", "A":
+		return sp.parseName("Weekday", ErrUnexpectedText)
+	case "b", "B", "h":
+		return sp.parseName("Month", ErrInvalidMonth)
+	case "p":
+		return sp.parsePeriod()
+	case "d":
+		return sp.parseNumber(&sp.parsed.Day, "Day", ErrInvalidDay, 1, 2, 1, 31)
+	case "e":
+		sp.skipSpace()
+		return sp.parseNumber(&sp.parsed.Day, "Day", ErrInvalidDay, 1, 2, 1, 31)
+	case "j":
+		if err := sp.parseNumber(&sp.parsed.Day, "Day", ErrInvalidDay, 1, 3, 1, 366); err != nil {
+			return err
+		}
+		// Three digits marks the day as the day of the year
+		n, _ := strconv.Atoi(sp.parsed.Day)
+		sp.parsed.Day = fmt.Sprintf("%03d", n)
+		return nil
+	case "m":
+		return sp.parseNumber(&sp.parsed.Month, "Month", ErrInvalidMonth, 1, 2, 1, 12)
+	case "y":
+		return sp.parseNumber(&sp.parsed.Year, "Year", ErrInvalidYear, 2, 2, 0, 99)
+	case "Y", "G":
+		return sp.parseNumber(&sp.parsed.Year, "Year", ErrInvalidYear, 4, 4, 0, 9999)
+	case "V":
+		return sp.parseNumber(&sp.parsed.Week, "Week", ErrInvalidDate, 1, 2, 1, 53)
+	case "u":
+		var wd string
+		if err := sp.parseNumber(&wd, "Weekday", ErrInvalidDate, 1, 1, 1, 7); err != nil {
+			return err
+		}
+		n, _ := strconv.Atoi(wd)
+		sp.parsed.Weekday = sp.loc.DayNamesAbbr[n%7]
+		return nil
+	case "H":
+		return sp.parseNumber(&sp.parsed.Hour, "Hour", ErrInvalidHour, 1, 2, 0, 23)
+	case "I":
+		return sp.parseNumber(&sp.parsed.Hour, "Hour", ErrInvalidHour, 1, 2, 1, 12)
+	case "l":
+		sp.skipSpace()
+		return sp.parseNumber(&sp.parsed.Hour, "Hour", ErrInvalidHour, 1, 2, 1, 12)
+	case "M":
+		return sp.parseNumber(&sp.parsed.Minute, "Minute", ErrInvalidMinute, 1, 2, 0, 59)
+	case "S":
+		return sp.parseNumber(&sp.parsed.Second, "Second", ErrInvalidSecond, 1, 2, 0, 61)
+	case "f":
+		return sp.parseNumber(&sp.parsed.FracSecond, "FracSecond", ErrInvalidSecond, 1, 9, 0, 999999999)
+	case "z", ":z":
+		return sp.parseOffset()
+	case "Z":
+		return sp.parseZone()
+	}
+	if alias, ok := strftimeAliases[d]; ok {
+		return sp.parse(alias)
+	}
+	return sp.err(ErrUnexpectedText, "", "unknown directive: %%%v", d)
+}
+
+func (sp *strptimeParser) parseNumber(field *string, name string, code ErrorCode, min int, max int, low int, high int) error {
+	n := 0
+	for sp.pos+n < len(sp.text) && n < max && isDigit(sp.text[sp.pos+n]) {
+		n++
+	}
+	if n < min {
+		return sp.err(code, name, "expecting %v", strings.ToLower(name))
+	}
+	v := sp.text[sp.pos : sp.pos+n]
+	if i, _ := strconv.Atoi(v); i < low || i > high {
+		return sp.err(code, name, "invalid %v: %v", strings.ToLower(name), v)
+	}
+	sp.pos += n
+	*field = v
+	return nil
+}
+
+// parseName matches the longest month or day name and sets the field to
+// the abbreviated name. Names are compared using the keys of the locale.
+func (sp *strptimeParser) parseName(field string, code ErrorCode) error {
+	names := sp.loc.DayNum
+	if field == "Month" {
+		names = sp.loc.MonthNum
+	}
+	n, length := sp.matchKey(names, nil)
+	if length == 0 {
+		return sp.err(code, field, "expecting %v name", strings.ToLower(field))
+	}
+	sp.pos += length
+	if field == "Month" {
+		sp.parsed.Month = sp.loc.MonthNamesAbbr[n-1]
+	} else {
+		sp.parsed.Weekday = sp.loc.DayNamesAbbr[n]
+	}
+	return nil
+}
+
+func (sp *strptimeParser) parsePeriod() error {
+	n, length := sp.matchKey(sp.loc.PeriodNum, func(n int) bool {
+		return n == locale.AM || n == locale.PM
+	})
+	if length == 0 {
+		return sp.err(ErrUnexpectedText, "Period", "expecting period")
+	}
+	sp.pos += length
+	sp.parsed.Period = sp.loc.PeriodNamesAbbr.Main(n)
+	return nil
+}
+
+// matchKey finds the longest text at the current position with a key
+// that is in names and returns the value for that key and the length of
+// the text. Only values that are accepted are used, if accept is not nil.
+// Returns a length of zero if there is no match.
+func (sp *strptimeParser) matchKey(names map[string]int, accept func(int) bool) (int, int) {
+	rest := sp.text[sp.pos:]
+	if i := strings.IndexFunc(rest, unicode.IsDigit); i >= 0 {
+		rest = rest[:i]
+	}
+	for end := len(rest); end > 0; end-- {
+		if end < len(rest) && !utf8.RuneStart(rest[end]) {
+			continue
+		}
+		n, ok := names[sp.loc.Key(rest[:end])]
+		if ok && (accept == nil || accept(n)) {
+			return n, end
+		}
+	}
+	return 0, 0
+}
+
+func (sp *strptimeParser) parseOffset() error {
+	if sp.pos < len(sp.text) && sp.text[sp.pos] == 'Z' {
+		sp.pos++
+		sp.parsed.Zone = "Z"
+		sp.parsed.Offset = "+0000"
+		return nil
+	}
+	if sp.pos >= len(sp.text) || (sp.text[sp.pos] != '+' && sp.text[sp.pos] != '-') {
+		return sp.err(ErrInvalidOffset, "Offset", "expecting offset")
+	}
+	sign := sp.text[sp.pos : sp.pos+1]
+	sp.pos++
+	var hours, minutes string
+	if err := sp.parseNumber(&hours, "Offset", ErrInvalidOffset, 2, 2, 0, 23); err != nil {
+		return err
+	}
+	if sp.pos < len(sp.text) && sp.text[sp.pos] == ':' {
+		sp.pos++
+	}
+	if err := sp.parseNumber(&minutes, "Offset", ErrInvalidOffset, 2, 2, 0, 59); err != nil {
+		return err
+	}
+	sp.parsed.Offset = sign + hours + minutes
+	return nil
+}
+
+func (sp *strptimeParser) parseZone() error {
+	end := sp.pos
+	for end < len(sp.text) {
+		ch, w := utf8.DecodeRuneInString(sp.text[end:])
+		if !unicode.IsLetter(ch) && !unicode.Is(unicode.Mn, ch) {
+			break
+		}
+		end += w
+	}
+	zone, offset, ok := sp.loc.LookupZone(sp.text[sp.pos:end])
+	if !ok {
+		return sp.err(ErrInvalidZone, "Zone", "unknown time zone")
+	}
+	sp.pos = end
+	sp.parsed.Zone = zone
+	sp.parsed.Offset = offset
+	return nil
+}
+
+func (sp *strptimeParser) skipSpace() {
+	if sp.pos < len(sp.text) && sp.text[sp.pos] == ' ' {
+		sp.pos++
+	}
+}
+
+func (sp *strptimeParser) err(code ErrorCode, field string, format string, a ...any) error {
+	tok := Token{End, "", sp.pos + 1}
+	if sp.pos < len(sp.text) {
+		tok = Token{Text, sp.text[sp.pos:], sp.pos + 1}
+	}
+	return &ParseError{
+		Code:  code,
+		Field: field,
+		Token: tok,
+		State: "strptime",
+		Msg:   fmt.Sprintf(format, a...),
+	}
+}
@@ -0,0 +1,132 @@
+package ptime
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/blackchip-org/ptime/locale"
+)
+
+func TestFormatStrftime(t *testing.T) {
+	mst := time.FixedZone("MST", -7*3600)
+	ts := time.Date(2006, 1, 2, 15, 4, 5, 123456789, mst)
+	tests := []struct {
+		loc    *locale.Locale
+		layout string
+		out    string
+	}{
+		{locale.EnUS, "%Y-%m-%d %H:%M:%S", "2006-01-02 15:04:05"},
+		{locale.EnUS, "%F %T.%f %z", "2006-01-02 15:04:05.123456 -0700"},
+		{locale.EnUS, "%a, %d %b %Y %I:%M %p %Z", "Mon, 02 Jan 2006 03:04 PM MST"},
+		{locale.EnUS, "%A %B %e %l%p", "Monday January  2  3PM"},
+		{locale.EnUS, "%D %R %:z", "01/02/06 15:04 -07:00"},
+		{locale.EnUS, "%j %G-W%V-%u", "002 2006-W01-1"},
+		{locale.EnUS, "%c", "Mon Jan  2 15:04:05 2006"},
+		{locale.EnUS, "100%% %y", "100% 06"},
+		{locale.EnUS, "%Q", "!(BADFIELD)"},
+		{locale.FrFR, "%A %d %B %Y", "lundi 02 janvier 2006"},
+		{locale.FrFR, "%a %d %b", "lun. 02 janv."},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout, func(t *testing.T) {
+			out := FormatStrftime(test.loc, test.layout, ts)
+			if out != test.out {
+				t.Errorf("\n have: %q \n want: %q", out, test.out)
+			}
+		})
+	}
+}
+
+func TestParseStrptime(t *testing.T) {
+	def := locale.FrFR.Def
+	def.FoldDiacritics = true
+	fold := locale.MustNew(def)
+	def = locale.FrFR.Def
+	def.ZoneNamesShort = map[string]string{"HNÉ": "-0500"}
+	frCA := locale.MustNew(def)
+
+	tests := []struct {
+		loc    *locale.Locale
+		layout string
+		text   string
+		parsed Parsed
+	}{
+		{locale.EnUS, "%Y-%m-%d %H:%M:%S", "2006-01-02 15:04:05", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05",
+		}},
+		{locale.EnUS, "%a, %d %b %Y %I:%M %p %Z", "Mon, 02 Jan 2006 03:04 pm MST", Parsed{
+			Weekday: "Mon", Year: "2006", Month: "Jan", Day: "02", Hour: "03", Minute: "04",
+			Period: "PM", Zone: "MST", Offset: "-0700",
+		}},
+		{locale.EnUS, "%B %d, %Y", "january 2,   2006", Parsed{
+			Year: "2006", Month: "Jan", Day: "2",
+		}},
+		{locale.EnUS, "%FT%T.%f%z", "2006-01-02T15:04:05.123-07:00", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04", Second: "05",
+			FracSecond: "123", Offset: "-0700",
+		}},
+		{locale.EnUS, "%Y%j", "20062", Parsed{Year: "2006", Day: "002"}},
+		{locale.EnUS, "%Y-%m-%d %H:%M", "٢٠٠٦-٠١-٠٢ １５：０４", Parsed{
+			Year: "2006", Month: "01", Day: "02", Hour: "15", Minute: "04",
+		}},
+		{locale.EnUS, "%G-W%V-%u", "2006-W01-7", Parsed{Year: "2006", Week: "01", Weekday: "Sun"}},
+		{locale.FrFR, "%A %d %B %Y", "lundi 02 janvier 2006", Parsed{
+			Weekday: "lun.", Year: "2006", Month: "janv.", Day: "02",
+		}},
+		{locale.FrFR, "%d %b %Y", "02 janv 2006", Parsed{Year: "2006", Month: "janv.", Day: "02"}},
+		{locale.FrFR, "%d %B %Y", "02 fe\u0301vrier 2006", Parsed{Year: "2006", Month: "févr.", Day: "02"}},
+		{locale.FrFR, "%d %b %Y", "02\u00a0sept.\u00a02006", Parsed{Year: "2006", Month: "sept.", Day: "02"}},
+		{locale.FrFR, "%d %b %Y", "02 SEPT 2006", Parsed{Year: "2006", Month: "sept.", Day: "02"}},
+		{fold, "%d %B %Y", "02 fevrier 2006", Parsed{Year: "2006", Month: "févr.", Day: "02"}},
+		{locale.EnUS, "%H:%M %Z", "15:04 mst", Parsed{Hour: "15", Minute: "04", Zone: "MST", Offset: "-0700"}},
+		{frCA, "%H:%M %Z", "15:04 HNÉ", Parsed{Hour: "15", Minute: "04", Zone: "HNÉ", Offset: "-0500"}},
+		{frCA, "%H:%M %Z", "15:04 hne\u0301", Parsed{Hour: "15", Minute: "04", Zone: "HNÉ", Offset: "-0500"}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			parsed, err := ParseStrptime(test.loc, test.layout, test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !reflect.DeepEqual(parsed, test.parsed) {
+				t.Errorf("\n have: %v \n want: %v", parsed, test.parsed)
+			}
+		})
+	}
+}
+
+func TestParseStrptimeError(t *testing.T) {
+	tests := []struct {
+		layout string
+		text   string
+		code   ErrorCode
+		pos    int
+	}{
+		{"%Y-%m-%d", "2006-13-02", ErrInvalidMonth, 6},
+		{"%Y-%m-%d", "２００６-13-02", ErrInvalidMonth, 14},
+		{"%Y-%m-%d", "2006/01/02", ErrUnexpectedText, 5},
+		{"%Y-%m-%d", "2006-01-02 15:04", ErrUnexpectedText, 11},
+		{"%Y-%m-%d", "06-01-02", ErrInvalidYear, 1},
+		{"%H:%M", "25:00", ErrInvalidHour, 1},
+		{"%I %p", "3 xm", ErrUnexpectedText, 3},
+		{"%b %d", "Foo 02", ErrInvalidMonth, 1},
+		{"%Y %Q", "2006 1", ErrUnexpectedText, 6},
+	}
+
+	for _, test := range tests {
+		t.Run(test.layout+":"+test.text, func(t *testing.T) {
+			_, err := ParseStrptime(locale.EnUS, test.layout, test.text)
+			var perr *ParseError
+			if !errors.As(err, &perr) {
+				t.Fatalf("expected parse error, have: %v", err)
+			}
+			if perr.Code != test.code || perr.Token.Pos != test.pos {
+				t.Errorf("\n have: %v at %v (%v) \n want: %v at %v", perr.Code, perr.Token.Pos, perr, test.code, test.pos)
+			}
+		})
+	}
+}
@@ -8,116 +8,230 @@ import (
 	"github.com/blackchip-org/ptime/locale"
 )
 
+// Time resolves the parsed fields to a time using the default options of a
+// Parser. Missing fields are taken from now.
 func Time(l *locale.Locale, p Parsed, now time.Time) (time.Time, error) {
+	return NewParser(l).Time(p, now)
+}
+
+// Time resolves the parsed fields to a time. Missing fields are taken from
+// now.
+func (p *Parser) Time(parsed Parsed, now time.Time) (time.Time, error) {
+	l := p.loc
 	var year, mon, day, hour, min, sec, nsec int
 	var loc *time.Location
 	var err error
 
-	if p.Year != "" {
-		year, err = strconv.Atoi(p.Year)
+	if parsed.Relative != "" {
+		now, err = relativeTime(l, parsed, now)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid year: %v", p.Year)
+			return time.Time{}, err
+		}
+		if unit, ok := l.UnitNum[l.Key(parsed.RelativeUnit)]; ok && isClockUnit(unit) {
+			hour, min, sec = now.Clock()
+			nsec = now.Nanosecond()
 		}
-		if len(p.Year) == 2 {
-			year = now.Year()/1000*1000 + year
+	}
+
+	if parsed.Year != "" {
+		year, err = strconv.Atoi(parsed.Year)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid year: %v", parsed.Year)
+		}
+		if len(parsed.Year) == 2 && parsed.Era == "" {
+			year = p.twoDigitYear(year, now)
 		}
 	} else {
 		year = now.Year()
 	}
 
-	if p.Month != "" {
-		m, ok := l.MonthNum[l.Key(p.Month)]
+	// Years in an era start at one and there is no year zero. The year
+	// before 1 AD is 1 BC which is year 0 when counted astronomically.
+	if parsed.Era != "" {
+		era, ok := l.EraNum[l.Key(parsed.Era)]
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid era: %v", parsed.Era)
+		}
+		if year < 1 {
+			return time.Time{}, fmt.Errorf("invalid year for era: %v", parsed.Year)
+		}
+		if era == locale.BC {
+			year = 1 - year
+		}
+	}
+
+	if parsed.Month != "" {
+		m, ok := l.MonthNum[l.Key(parsed.Month)]
 		if ok {
 			mon = m
 		}
 		if !ok {
-			mon, err = strconv.Atoi(p.Month)
+			mon, err = strconv.Atoi(parsed.Month)
 			if err != nil {
-				return time.Time{}, fmt.Errorf("invalid month: %v", p.Month)
+				return time.Time{}, fmt.Errorf("invalid month: %v", parsed.Month)
 			}
 		}
 	} else {
 		mon = int(now.Month())
 	}
 
-	if p.Day != "" {
-		day, err = strconv.Atoi(p.Day)
+	if parsed.Day != "" {
+		day, err = strconv.Atoi(parsed.Day)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid day: %v", p.Month)
+			return time.Time{}, fmt.Errorf("invalid day: %v", parsed.Month)
 		}
 
-		if len(p.Day) == 3 {
-			if p.Month != "" {
+		if len(parsed.Day) == 3 {
+			if parsed.Month != "" {
 				return time.Time{}, fmt.Errorf("must use either ordinal day or month")
 			}
 			mon = 1
 		}
 	} else {
-		if p.Year == "" && p.Month == "" {
+		if parsed.Year == "" && parsed.Month == "" {
 			day = now.Day()
 		} else {
 			day = 1
 		}
 	}
 
-	if p.Hour != "" {
-		hour, err = strconv.Atoi(p.Hour)
+	weekday := -1
+	if parsed.Weekday != "" {
+		n, ok := l.DayNum[l.Key(parsed.Weekday)]
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid weekday: %v", parsed.Weekday)
+		}
+		weekday = n
+	}
+
+	if parsed.Week != "" {
+		if parsed.Month != "" || parsed.Day != "" {
+			return time.Time{}, fmt.Errorf("must use either week or month and day")
+		}
+		week, err := strconv.Atoi(parsed.Week)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid hour: %v", p.Hour)
+			return time.Time{}, fmt.Errorf("invalid week: %v", parsed.Week)
+		}
+		if parsed.Year == "" {
+			year, _ = now.ISOWeek()
+		}
+		if week < 1 || week > isoWeeks(year) {
+			return time.Time{}, fmt.Errorf("invalid week: %v", parsed.Week)
 		}
+		wd := 1
+		if weekday >= 0 {
+			wd = (weekday+6)%7 + 1
+		}
+		d := isoWeekDate(year, week, wd, time.UTC)
+		year, mon, day = d.Year(), int(d.Month()), d.Day()
+	}
+	year, mon, day = p.resolveDate(parsed, weekday, year, mon, day, now)
+	if err := p.validateDate(parsed, year, mon, day); err != nil {
+		return time.Time{}, err
+	}
+	year, mon, day, err = p.checkWeekday(parsed, weekday, year, mon, day)
+	if err != nil {
+		return time.Time{}, err
+	}
 
-		if p.Period != "" {
-			num, ok := l.PeriodNum[l.Key(p.Period)]
+	if parsed.Hour != "" {
+		hour, err = strconv.Atoi(parsed.Hour)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid hour: %v", parsed.Hour)
+		}
+	}
+	if parsed.Period != "" {
+		num, ok := l.PeriodNum[l.Key(parsed.Period)]
+		if !ok {
+			return time.Time{}, fmt.Errorf("invalid period: %v", parsed.Period)
+		}
+		if parsed.Hour != "" || num == locale.Noon || num == locale.Midnight {
+			hour, ok = clockHour(num, hour, parsed.Hour != "")
 			if !ok {
-				return time.Time{}, fmt.Errorf("invalid period: %v", p.Period)
-			}
-			if num == int(locale.PM) || num == int(locale.Midnight) {
-				hour += 12
+				return time.Time{}, fmt.Errorf("invalid hour for %v: %v", parsed.Period, parsed.Hour)
 			}
 		}
 	}
 
-	if p.Minute != "" {
-		min, err = strconv.Atoi(p.Minute)
+	if parsed.Minute != "" {
+		min, err = strconv.Atoi(parsed.Minute)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid minute: %v", p.Minute)
+			return time.Time{}, fmt.Errorf("invalid minute: %v", parsed.Minute)
 		}
 	}
-	if p.Second != "" {
-		sec, err = strconv.Atoi(p.Second)
+	if parsed.Second != "" {
+		sec, err = strconv.Atoi(parsed.Second)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid second: %v", p.Second)
+			return time.Time{}, fmt.Errorf("invalid second: %v", parsed.Second)
 		}
 	}
-	if p.FracSecond != "" {
-		fsec, err := strconv.Atoi(p.FracSecond)
-		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid fractional second: %v", p.FracSecond)
+	if parsed.FracSecond != "" {
+		if _, err := strconv.Atoi(parsed.FracSecond); err != nil {
+			return time.Time{}, fmt.Errorf("invalid fractional second: %v", parsed.FracSecond)
 		}
-		nsec = fsecToNsec(fsec)
+		nsec = fracToNsec(parsed.FracSecond)
 	}
 
-	if p.Offset != "" {
-		o, err := strconv.Atoi(p.Offset)
+	var offset int
+	if parsed.Offset != "" {
+		o, err := strconv.Atoi(parsed.Offset)
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid offset: %v", p.Offset)
+			return time.Time{}, fmt.Errorf("invalid offset: %v", parsed.Offset)
 		}
 		oh := o / 100
 		om := o % 100
-		offset := (oh * 3600) + (om * 60)
-		loc = time.FixedZone(p.Zone, offset)
-	} else {
+		offset = (oh * 3600) + (om * 60)
+	}
+
+	switch {
+	case parsed.Location != "":
+		loc, err = time.LoadLocation(parsed.Location)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid location: %v", parsed.Location)
+		}
+		t := time.Date(year, time.Month(mon), day, hour, min, sec, nsec, loc)
+		if _, o := t.Zone(); parsed.Offset != "" && o != offset {
+			return time.Time{}, fmt.Errorf("offset '%v' does not match location '%v'", parsed.Offset, parsed.Location)
+		}
+		return t, nil
+	case parsed.Offset != "":
+		loc = time.FixedZone(parsed.Zone, offset)
+		// Use the real location for a zone abbreviation if it is in effect
+		// at that time so that the result has the correct daylight saving
+		// time rules.
+		if name, ok := l.ZoneLocations[parsed.Zone]; ok {
+			if zl, err := time.LoadLocation(name); err == nil {
+				t := time.Date(year, time.Month(mon), day, hour, min, sec, nsec, zl)
+				if z, o := t.Zone(); z == parsed.Zone && o == offset {
+					return t, nil
+				}
+			}
+		}
+	default:
 		loc = now.Location()
 	}
 
 	return time.Date(year, time.Month(mon), day, hour, min, sec, nsec, loc), nil
 }
 
-// There must be a better way to do this
-func fsecToNsec(fsec int) int {
-	sec, err := strconv.ParseFloat(fmt.Sprintf(".%v", fsec), 64)
-	if err != nil {
-		panic(err)
+// clockHour converts an hour on a 12-hour clock to an hour on a 24-hour
+// clock. The hours for AM and PM go from 1 to 12 where "12am" is midnight
+// and "12pm" is noon. Noon and midnight can be used without an hour or with
+// an hour of 12, as in "12 noon". Midnight is the start of the day.
+func clockHour(period int, hour int, hasHour bool) (int, bool) {
+	switch period {
+	case locale.AM, locale.PM:
+		if hour < 1 || hour > 12 {
+			return 0, false
+		}
+		if period == locale.PM {
+			return hour%12 + 12, true
+		}
+		return hour % 12, true
+	case locale.Noon:
+		return 12, !hasHour || hour == 12
+	case locale.Midnight:
+		return 0, !hasHour || hour == 12
 	}
-	return int(sec * 1e9)
+	return 0, false
 }
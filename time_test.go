@@ -1,6 +1,7 @@
 package ptime
 
 import (
+	"errors"
 	"testing"
 	"time"
 
@@ -52,6 +53,36 @@ func TestTimeEnUS(t *testing.T) {
 			Parsed{Hour: "10", Minute: "33", Period: "pm"},
 			time.Date(2006, 01, 02, 22, 33, 0, 0, nowZ),
 		},
+		{
+			"12pm",
+			Parsed{Hour: "12", Period: "PM"},
+			time.Date(2006, 01, 02, 12, 0, 0, 0, nowZ),
+		},
+		{
+			"12:30am",
+			Parsed{Hour: "12", Minute: "30", Period: "AM"},
+			time.Date(2006, 01, 02, 0, 30, 0, 0, nowZ),
+		},
+		{
+			"noon",
+			Parsed{Period: "noon"},
+			time.Date(2006, 01, 02, 12, 0, 0, 0, nowZ),
+		},
+		{
+			"midnight",
+			Parsed{Period: "midnight"},
+			time.Date(2006, 01, 02, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"12 midnight",
+			Parsed{Hour: "12", Period: "midnight"},
+			time.Date(2006, 01, 02, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"noon tomorrow",
+			Parsed{Period: "noon", Relative: "+1", RelativeUnit: "day"},
+			time.Date(2006, 01, 03, 12, 0, 0, 0, nowZ),
+		},
 		{
 			"22:33:44",
 			Parsed{Hour: "22", Minute: "33", Second: "44"},
@@ -60,7 +91,12 @@ func TestTimeEnUS(t *testing.T) {
 		{
 			"22:33:44.55",
 			Parsed{Hour: "22", Minute: "33", Second: "44", FracSecond: "55"},
-			time.Date(2006, 01, 02, 22, 33, 44, fsecToNsec(55), nowZ),
+			time.Date(2006, 01, 02, 22, 33, 44, 550000000, nowZ),
+		},
+		{
+			"22:33:44.05",
+			Parsed{Hour: "22", Minute: "33", Second: "44", FracSecond: "05"},
+			time.Date(2006, 01, 02, 22, 33, 44, 50000000, nowZ),
 		},
 		{
 			"22:33:44 MST -0700",
@@ -72,6 +108,76 @@ func TestTimeEnUS(t *testing.T) {
 			Parsed{Hour: "22", Minute: "33", Second: "44", Zone: "EST", Offset: "-0500"},
 			time.Date(2006, 01, 02, 22, 33, 44, 0, estZ),
 		},
+		{
+			"tomorrow",
+			Parsed{Relative: "+1", RelativeUnit: "day"},
+			time.Date(2006, 01, 03, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"tomorrow 3pm",
+			Parsed{Hour: "3", Period: "PM", Relative: "+1", RelativeUnit: "day"},
+			time.Date(2006, 01, 03, 15, 0, 0, 0, nowZ),
+		},
+		{
+			"next Friday",
+			Parsed{Weekday: "Fri", Relative: "+1"},
+			time.Date(2006, 01, 06, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"next Friday 5pm",
+			Parsed{Weekday: "Fri", Hour: "5", Period: "PM", Relative: "+1"},
+			time.Date(2006, 01, 06, 17, 0, 0, 0, nowZ),
+		},
+		{
+			"next Monday",
+			Parsed{Weekday: "Mon", Relative: "+1"},
+			time.Date(2006, 01, 9, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"last Monday",
+			Parsed{Weekday: "Mon", Relative: "-1"},
+			time.Date(2005, 12, 26, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"in 3 hours",
+			Parsed{Relative: "+3", RelativeUnit: "hour"},
+			time.Date(2006, 01, 02, 18, 04, 05, 0, nowZ),
+		},
+		{
+			"2 weeks ago",
+			Parsed{Relative: "-2", RelativeUnit: "week"},
+			time.Date(2005, 12, 19, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"Jul 4 2006 12:00 America/New_York",
+			Parsed{Year: "2006", Month: "Jul", Day: "4", Hour: "12", Minute: "00", Location: "America/New_York"},
+			time.Date(2006, 07, 04, 12, 0, 0, 0, time.FixedZone("EDT", -4*3600)),
+		},
+		{
+			"Jul 4 2006 12:00 EST",
+			Parsed{Year: "2006", Month: "Jul", Day: "4", Hour: "12", Minute: "00", Zone: "EST", Offset: "-0500"},
+			time.Date(2006, 07, 04, 12, 0, 0, 0, estZ),
+		},
+		{
+			"March 15 44 BC",
+			Parsed{Year: "44", Era: "BC", Month: "Mar", Day: "15"},
+			time.Date(-43, 3, 15, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"Oct 14 66 AD",
+			Parsed{Year: "66", Era: "AD", Month: "Oct", Day: "14"},
+			time.Date(66, 10, 14, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"-0043-03-15",
+			Parsed{Year: "-0043", Month: "03", Day: "15"},
+			time.Date(-43, 3, 15, 0, 0, 0, 0, nowZ),
+		},
+		{
+			"10000-01-02",
+			Parsed{Year: "10000", Month: "01", Day: "02"},
+			time.Date(10000, 1, 2, 0, 0, 0, 0, nowZ),
+		},
 	}
 
 	for _, test := range tests {
@@ -87,6 +193,53 @@ func TestTimeEnUS(t *testing.T) {
 	}
 }
 
+func TestTimeLocation(t *testing.T) {
+	now := time.Date(2006, 01, 02, 15, 04, 05, 00, time.UTC)
+	tests := []struct {
+		name     string
+		parsed   Parsed
+		location string
+		zone     string
+	}{
+		{
+			"Jan 2 2006 12:00 Europe/Paris",
+			Parsed{Year: "2006", Month: "Jan", Day: "2", Hour: "12", Location: "Europe/Paris"},
+			"Europe/Paris",
+			"CET",
+		},
+		{
+			"Jul 4 2006 12:00 EDT",
+			Parsed{Year: "2006", Month: "Jul", Day: "4", Hour: "12", Zone: "EDT", Offset: "-0400"},
+			"America/New_York",
+			"EDT",
+		},
+		{
+			"Jul 4 2006 12:00 EST",
+			Parsed{Year: "2006", Month: "Jul", Day: "4", Hour: "12", Zone: "EST", Offset: "-0500"},
+			"EST",
+			"EST",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			tt, err := Time(locale.EnUS, test.parsed, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			zone, _ := tt.Zone()
+			if tt.Location().String() != test.location || zone != test.zone {
+				t.Errorf("\n have: %v %v \n want: %v %v", tt.Location(), zone, test.location, test.zone)
+			}
+		})
+	}
+
+	_, err := Time(locale.EnUS, Parsed{Hour: "12", Offset: "+0100", Location: "America/New_York"}, now)
+	if err == nil {
+		t.Errorf("expected offset mismatch error")
+	}
+}
+
 func TestTimeFrFR(t *testing.T) {
 	nowZ := time.UTC
 	now := time.Date(2006, 01, 02, 15, 04, 05, 00, nowZ)
@@ -106,6 +259,11 @@ func TestTimeFrFR(t *testing.T) {
 			Parsed{Year: "2016", Month: "janv.", Day: "2"},
 			time.Date(2016, 1, 2, 0, 0, 0, 0, nowZ),
 		},
+		{
+			"dans 3 jours",
+			Parsed{Relative: "+3", RelativeUnit: "jour"},
+			time.Date(2006, 1, 5, 0, 0, 0, 0, nowZ),
+		},
 	}
 
 	for _, test := range tests {
@@ -120,3 +278,222 @@ func TestTimeFrFR(t *testing.T) {
 		})
 	}
 }
+
+func TestTwoDigitYear(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		policy TwoDigitYear
+		year   string
+		want   int
+	}{
+		{"default", nil, "99", 2099},
+		{"same century", SameCentury, "05", 2005},
+		{"sliding 80", SlidingWindow(80), "99", 1999},
+		{"sliding 80", SlidingWindow(80), "42", 2042},
+		{"sliding 80", SlidingWindow(80), "43", 1943},
+		{"pivot 1950", FixedPivot(1950), "49", 2049},
+		{"pivot 1950", FixedPivot(1950), "50", 1950},
+		{"past", PreferPast, "23", 2023},
+		{"past", PreferPast, "24", 1924},
+		{"future", PreferFuture, "23", 2023},
+		{"future", PreferFuture, "22", 2122},
+	}
+
+	for _, test := range tests {
+		t.Run(test.name+":"+test.year, func(t *testing.T) {
+			p := For(locale.EnUS)
+			p.Parser.TwoDigitYear = test.policy
+			parsed := Parsed{Year: test.year, Month: "1", Day: "2"}
+			// Every path that resolves a time uses the same policy
+			have, err := p.Time(parsed, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			start, end, err := p.TimeRange(parsed, parsed, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			ivStart, _, err := p.IntervalTime(Interval{Start: parsed, Duration: Duration{Days: 1}}, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			for _, tt := range []time.Time{have, start, end, ivStart} {
+				if tt.Year() != test.want {
+					t.Errorf("\n have: %v \n want: %v", tt.Year(), test.want)
+				}
+			}
+		})
+	}
+}
+
+func TestTimeResolve(t *testing.T) {
+	// Monday
+	now := time.Date(2006, 1, 2, 15, 4, 5, 0, time.UTC)
+	date := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 0, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		resolve Resolve
+		parsed  Parsed
+		want    time.Time
+	}{
+		{ResolveCurrent, Parsed{Weekday: "Fri"}, date(2006, 1, 2)},
+		{ResolveNext, Parsed{Weekday: "Fri"}, date(2006, 1, 6)},
+		{ResolvePrevious, Parsed{Weekday: "Fri"}, date(2005, 12, 30)},
+		{ResolveNearest, Parsed{Weekday: "Fri"}, date(2005, 12, 30)},
+		{ResolveNearest, Parsed{Weekday: "Wed"}, date(2006, 1, 4)},
+		{ResolveNext, Parsed{Weekday: "Mon"}, date(2006, 1, 2)},
+		{ResolvePrevious, Parsed{Weekday: "Mon"}, date(2006, 1, 2)},
+		{ResolveNext, Parsed{Weekday: "Fri", Hour: "3", Period: "PM"}, time.Date(2006, 1, 6, 15, 0, 0, 0, time.UTC)},
+		{ResolveCurrent, Parsed{Month: "Dec", Day: "25"}, date(2006, 12, 25)},
+		{ResolveNext, Parsed{Month: "Dec", Day: "25"}, date(2006, 12, 25)},
+		{ResolvePrevious, Parsed{Month: "Dec", Day: "25"}, date(2005, 12, 25)},
+		{ResolveNearest, Parsed{Month: "Dec", Day: "25"}, date(2005, 12, 25)},
+		{ResolveNext, Parsed{Month: "Jan", Day: "2"}, date(2006, 1, 2)},
+		{ResolvePrevious, Parsed{Month: "Jan"}, date(2006, 1, 1)},
+		{ResolveNext, Parsed{Month: "Jan"}, date(2006, 1, 1)},
+		{ResolvePrevious, Parsed{Month: "Nov"}, date(2005, 11, 1)},
+		{ResolveNearest, Parsed{Month: "Nov"}, date(2005, 11, 1)},
+		{ResolveNext, Parsed{Month: "Nov"}, date(2006, 11, 1)},
+		{ResolvePrevious, Parsed{Day: "31"}, date(2005, 12, 31)},
+		{ResolveNext, Parsed{Day: "31"}, date(2006, 1, 31)},
+		{ResolveNext, Parsed{Year: "2005", Month: "Dec", Day: "25"}, date(2005, 12, 25)},
+		{ResolveNext, Parsed{Weekday: "Fri", Relative: "-1"}, date(2005, 12, 30)},
+	}
+
+	for _, test := range tests {
+		t.Run(test.resolve.String()+":"+test.parsed.String(), func(t *testing.T) {
+			p := NewParser(locale.EnUS)
+			p.Resolve = test.resolve
+			have, err := p.Time(test.parsed, now)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !have.Equal(test.want) {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+}
+
+func TestTimeWeekday(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		mode   WeekdayMode
+		text   string
+		want   time.Time
+		errDay time.Weekday
+	}{
+		{WeekdayCheck, "Mon Jan 2 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), -1},
+		{WeekdayCheck, "Tue Jan 2 2006", time.Time{}, time.Monday},
+		{WeekdayIgnore, "Tue Jan 2 2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), -1},
+		{WeekdayCheck, "Mon 2/1/2006", time.Time{}, time.Wednesday},
+		{WeekdayRepair, "Mon 2/1/2006", time.Date(2006, 1, 2, 0, 0, 0, 0, time.UTC), -1},
+		{WeekdayRepair, "Wed 2/1/2006", time.Date(2006, 2, 1, 0, 0, 0, 0, time.UTC), -1},
+		{WeekdayRepair, "Tue 2/1/2006", time.Time{}, time.Wednesday},
+		{WeekdayRepair, "Fri Jan 2 2006", time.Time{}, time.Monday},
+		{WeekdayRepair, "Mon 1/13/2006", time.Time{}, time.Friday},
+		{WeekdayRepair, "Wed 2006-01-03", time.Time{}, time.Tuesday},
+	}
+
+	for _, test := range tests {
+		t.Run(test.text, func(t *testing.T) {
+			p := For(locale.EnUS)
+			p.Parser.WeekdayMode = test.mode
+			parsed, err := p.Parse(test.text)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			have, err := p.Time(parsed, now)
+			if test.errDay >= 0 {
+				var werr *WeekdayError
+				if !errors.As(err, &werr) {
+					t.Fatalf("expected weekday error, have: %v", err)
+				}
+				if werr.Weekday != parsed.Weekday || werr.Date.Weekday() != test.errDay {
+					t.Errorf("unexpected error: %v", werr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !have.Equal(test.want) {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+}
+
+func TestTimeWeekdayNoYear(t *testing.T) {
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	p := For(locale.EnUS)
+	parsed, err := p.Parse("Mon, Jan 2")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	have, err := p.Time(parsed, now)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC)
+	if !have.Equal(want) {
+		t.Errorf("\n have: %v \n want: %v", have, want)
+	}
+}
+
+func TestTimeCalendar(t *testing.T) {
+	now := time.Date(2023, 6, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		parsed Parsed
+		want   time.Time
+		err    string
+	}{
+		{Parsed{Year: "2024", Month: "02", Day: "29"}, time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC), ""},
+		{Parsed{Year: "2000", Month: "Feb", Day: "29"}, time.Date(2000, 2, 29, 0, 0, 0, 0, time.UTC), ""},
+		{Parsed{Year: "2024", Day: "366"}, time.Date(2024, 12, 31, 0, 0, 0, 0, time.UTC), ""},
+		{Parsed{Year: "2023", Month: "02", Day: "29"}, time.Time{}, "February 29 is not in 2023, which is not a leap year"},
+		{Parsed{Year: "1900", Month: "02", Day: "29"}, time.Time{}, "February 29 is not in 1900, which is not a leap year"},
+		{Parsed{Year: "2023", Month: "02", Day: "30"}, time.Time{}, "day 30 is not in February 2023, which has 28 days"},
+		{Parsed{Year: "2023", Month: "Apr", Day: "31"}, time.Time{}, "day 31 is not in April 2023, which has 30 days"},
+		{Parsed{Year: "2023", Day: "366"}, time.Time{}, "day 366 is not in 2023, which is not a leap year"},
+		{Parsed{Year: "2024", Day: "367"}, time.Time{}, "day 367 is not in 2024, which has 366 days"},
+		{Parsed{Year: "2023", Month: "13", Day: "01"}, time.Time{}, "invalid month: 13"},
+	}
+
+	p := NewParser(locale.EnUS)
+	for _, test := range tests {
+		t.Run(test.parsed.String(), func(t *testing.T) {
+			have, err := p.Time(test.parsed, now)
+			if test.err != "" {
+				var derr *DateError
+				if !errors.As(err, &derr) {
+					t.Fatalf("expected date error, have: %v", err)
+				}
+				if derr.Error() != test.err {
+					t.Errorf("\n have: %v \n want: %v", derr, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !have.Equal(test.want) {
+				t.Errorf("\n have: %v \n want: %v", have, test.want)
+			}
+		})
+	}
+
+	t.Run("normalize", func(t *testing.T) {
+		p := NewParser(locale.EnUS)
+		p.Normalize = true
+		have, err := p.Time(Parsed{Year: "2023", Month: "02", Day: "30"}, now)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if want := time.Date(2023, 3, 2, 0, 0, 0, 0, time.UTC); !have.Equal(want) {
+			t.Errorf("\n have: %v \n want: %v", have, want)
+		}
+	})
+}
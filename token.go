@@ -29,6 +29,11 @@ type Token struct {
 	Pos  int
 }
 
+// End returns the position just after the last character of the token.
+func (t Token) End() int {
+	return t.Pos + len(t.Val)
+}
+
 func (t Token) String() string {
 	return fmt.Sprintf("%v '%v' (c%v)", t.Type, t.Val, t.Pos)
 }
@@ -0,0 +1,8 @@
+//go:build !ptime_notzdata
+
+package ptime
+
+// The time zone database is embedded so that IANA time zone names can be
+// used on systems that do not have it installed. Build with the
+// ptime_notzdata tag to exclude it.
+import _ "time/tzdata"
@@ -0,0 +1,62 @@
+package ptime
+
+import "time"
+
+// TwoDigitYear returns the full year for a two digit year given the
+// reference time.
+type TwoDigitYear func(year int, now time.Time) int
+
+// SameCentury uses the century of the reference time so that "99" in 2023
+// is 2099. This is the default.
+func SameCentury(year int, now time.Time) int {
+	return now.Year()/100*100 + year
+}
+
+// SlidingWindow uses the 100 years that start the given number of years
+// before the reference time. With a value of 80, "99" in 2023 is 1999 and
+// "40" is 2040.
+func SlidingWindow(past int) TwoDigitYear {
+	return func(year int, now time.Time) int {
+		return yearInWindow(year, now.Year()-past)
+	}
+}
+
+// FixedPivot uses the 100 years that start with the pivot year. With a
+// pivot of 1950, "49" is 2049 and "50" is 1950.
+func FixedPivot(pivot int) TwoDigitYear {
+	return func(year int, now time.Time) int {
+		return yearInWindow(year, pivot)
+	}
+}
+
+// PreferPast uses the closest year that is not after the reference time.
+func PreferPast(year int, now time.Time) int {
+	return yearInWindow(year, now.Year()-99)
+}
+
+// PreferFuture uses the closest year that is not before the reference
+// time.
+func PreferFuture(year int, now time.Time) int {
+	return yearInWindow(year, now.Year())
+}
+
+// yearInWindow returns the first year on or after start that ends with
+// the two digits.
+func yearInWindow(year int, start int) int {
+	full := start - mod(start, 100) + year
+	if full < start {
+		full += 100
+	}
+	return full
+}
+
+func mod(a int, b int) int {
+	return (a%b + b) % b
+}
+
+func (p *Parser) twoDigitYear(year int, now time.Time) int {
+	if p.TwoDigitYear == nil {
+		return SameCentury(year, now)
+	}
+	return p.TwoDigitYear(year, now)
+}